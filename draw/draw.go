@@ -2,22 +2,26 @@ package draw
 
 import (
 	"io"
+	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/IvanSaratov/graph_methods/graph"
 )
 
-const dotTemplate = `strict {{.GraphType}} {
+const dotTemplate = `{{if .Strict}}strict {{end}}{{.GraphType}} {
 {{range $k, $v := .Attributes}}
 	{{$k}}="{{$v}}";
 {{end}}
 {{range $s := .Statements}}
-	"{{.Source}}" {{if .Target}}{{$.EdgeOperator}} "{{.Target}}" [ {{range $k, $v := .EdgeAttributes}}{{$k}}="{{$v}}", {{end}} weight={{.EdgeWeight}} ]{{else}}[ {{range $k, $v := .SourceAttributes}}{{$k}}="{{$v}}", {{end}} weight={{.SourceWeight}} ]{{end}};
+	"{{.Source}}" {{if .Target}}{{$.EdgeOperator}} "{{.Target}}" [ {{if .EdgeLabel}}label="{{.EdgeLabel}}", {{end}}{{range $k, $v := .EdgeAttributes}}{{$k}}="{{$v}}", {{end}} weight={{.EdgeWeight}} ]{{else}}[ {{if .SourceLabel}}label="{{.SourceLabel}}", {{end}}{{range $k, $v := .SourceAttributes}}{{$k}}="{{$v}}", {{end}} weight={{.SourceWeight}} ]{{end}};
 {{end}}
 }
 `
 
 type description struct {
+	// strict склеивает параллельные дуги, поэтому у мультиграфа его нет
+	Strict       bool
 	GraphType    string
 	Attributes   map[string]string
 	EdgeOperator string
@@ -27,10 +31,12 @@ type description struct {
 type statement struct {
 	Source           interface{}
 	Target           interface{}
-	SourceWeight     int
+	SourceWeight     string
 	SourceAttributes map[string]string
-	EdgeWeight       int
+	SourceLabel      string
+	EdgeWeight       string
 	EdgeAttributes   map[string]string
+	EdgeLabel        string
 }
 
 func DOT[K comparable, T any](g graph.Graph[K, T], w io.Writer, options ...func(*description)) error {
@@ -51,6 +57,7 @@ func GraphAttribute(key, value string) func(*description) {
 
 func generateDOT[K comparable, T any](g graph.Graph[K, T], options ...func(*description)) (description, error) {
 	desc := description{
+		Strict:       !g.Traits().IsMultigraph,
 		GraphType:    "graph",
 		Attributes:   make(map[string]string),
 		EdgeOperator: "--",
@@ -71,6 +78,9 @@ func generateDOT[K comparable, T any](g graph.Graph[K, T], options ...func(*desc
 		return desc, err
 	}
 
+	// Половины ненаправленной дуги мультиграфа различаются только направлением, рисуем одну
+	drawn := make(map[uint64]struct{})
+
 	for vertex, adjacencies := range adjacencyMap {
 		_, sourceProperties, err := g.VertexWithProperties(vertex)
 		if err != nil {
@@ -79,25 +89,65 @@ func generateDOT[K comparable, T any](g graph.Graph[K, T], options ...func(*desc
 
 		stmt := statement{
 			Source:           vertex,
-			SourceWeight:     sourceProperties.Weight,
+			SourceWeight:     weight(g.Traits(), sourceProperties.Weight, sourceProperties.FloatWeight),
 			SourceAttributes: sourceProperties.Attributes,
+			SourceLabel:      label(strings.Join(sourceProperties.Labels, ":"), sourceProperties.Attributes),
 		}
 		desc.Statements = append(desc.Statements, stmt)
 
 		for adjacency, edge := range adjacencies {
-			stmt := statement{
-				Source:         vertex,
-				Target:         adjacency,
-				EdgeWeight:     edge.Properties.Weight,
-				EdgeAttributes: edge.Properties.Attributes,
+			// В карте смежности только одна из параллельных дуг
+			edges := []graph.Edge[K]{edge}
+			if g.Traits().IsMultigraph {
+				if edges, err = g.EdgesBetween(vertex, adjacency); err != nil {
+					return desc, err
+				}
+			}
+
+			for _, edge := range edges {
+				if g.Traits().IsMultigraph && !g.Traits().IsDirected {
+					if _, ok := drawn[edge.Properties.ID]; ok {
+						continue
+					}
+					drawn[edge.Properties.ID] = struct{}{}
+				}
+
+				stmt := statement{
+					Source:         vertex,
+					Target:         adjacency,
+					EdgeWeight:     weight(g.Traits(), edge.Properties.Weight, edge.Properties.FloatWeight),
+					EdgeAttributes: edge.Properties.Attributes,
+					EdgeLabel:      label(edge.Properties.Type, edge.Properties.Attributes),
+				}
+				desc.Statements = append(desc.Statements, stmt)
 			}
-			desc.Statements = append(desc.Statements, stmt)
 		}
 	}
 
 	return desc, nil
 }
 
+// Вес целый или, у графа FloatWeighted, дробный.
+// DOT не понимает запись вида 1e+06, поэтому дробный вес пишется без экспоненты
+func weight(traits *graph.Traits, value int, float float64) string {
+	if traits.IsFloatWeighted {
+		return strconv.FormatFloat(float, 'f', -1, 64)
+	}
+
+	return strconv.Itoa(value)
+}
+
+// Метки вершины через двоеточие или тип дуги.
+// Атрибут label, заданный явно, важнее, и второй раз label не пишется.
+// Кавычки экранируются, иначе они закроют строку DOT раньше времени
+func label(value string, attributes map[string]string) string {
+	if _, ok := attributes["label"]; ok {
+		return ""
+	}
+
+	return strings.ReplaceAll(value, `"`, `\"`)
+}
+
 func renderDOT(w io.Writer, d description) error {
 	tpl, err := template.New("dotTemplate").Parse(dotTemplate)
 	if err != nil {
@@ -0,0 +1,92 @@
+package draw
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/IvanSaratov/graph_methods/graph"
+)
+
+// Большие и маленькие веса пишутся без экспоненты, иначе DOT их не разберёт
+func TestDOTWeightFormat(t *testing.T) {
+	g := graph.New(graph.IntHash, graph.Directed(), graph.FloatWeighted())
+
+	_ = g.AddVertex(1, graph.VertexWeight(2.5e-7))
+	_ = g.AddVertex(2)
+	_ = g.AddEdge(1, 2, graph.EdgeWeightFloat(1e6))
+
+	var buf bytes.Buffer
+	if err := DOT(g, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"weight=1000000 ", "weight=0.00000025 "} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("нет %q в\n%s", want, out)
+		}
+	}
+
+	if strings.Contains(out, "e+") || strings.Contains(out, "e-") {
+		t.Fatalf("вес с экспонентой:\n%s", out)
+	}
+}
+
+// У обычного графа вес целый, как и раньше
+func TestDOTIntWeight(t *testing.T) {
+	g := graph.New(graph.IntHash, graph.Directed(), graph.Weighted())
+
+	_ = g.AddVertex(1, graph.VertexWeight(7))
+	_ = g.AddVertex(2)
+	_ = g.AddEdge(1, 2, graph.EdgeWeight(3))
+	_ = g.AddEdge(2, 1, graph.EdgeWeightFloat(2.5))
+
+	var buf bytes.Buffer
+	if err := DOT(g, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"weight=7 ", "\"1\" -> \"2\" [  weight=3 ]", "\"2\" -> \"1\" [  weight=2 ]"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("нет %q в\n%s", want, out)
+		}
+	}
+}
+
+// Метки вершин и тип дуги попадают в label, кавычки в них экранируются
+func TestDOTLabels(t *testing.T) {
+	g := graph.New(graph.IntHash, graph.Directed())
+
+	_ = g.AddVertex(1, graph.VertexLabels("Person", "Admin"))
+	_ = g.AddVertex(2, graph.VertexLabels(`say "hi"`))
+	_ = g.AddVertex(3, graph.VertexLabels("Hidden"), func(p *graph.VertexProperties) {
+		p.Attributes["label"] = "own"
+	})
+	_ = g.AddEdge(1, 2, graph.EdgeType(`KNOWS "well"`))
+	_ = g.AddEdge(2, 3)
+
+	var buf bytes.Buffer
+	if err := DOT(g, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		`"1" [ label="Admin:Person",`,
+		`"2" [ label="say \"hi\"",`,
+		`"1" -> "2" [ label="KNOWS \"well\"",`,
+		`"2" -> "3" [  weight=0 ]`,
+		`"3" [ label="own",`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("нет %q в\n%s", want, out)
+		}
+	}
+
+	// Явный атрибут label не дублируется метками
+	if strings.Contains(out, "Hidden") {
+		t.Fatalf("метка вершины с атрибутом label попала в DOT:\n%s", out)
+	}
+}
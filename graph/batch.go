@@ -0,0 +1,312 @@
+package graph
+
+import "errors"
+
+// Хранилище, которое выполняет группу изменений под одной блокировкой.
+// Пока fn не вернётся, с tx больше никто не работает.
+// Если fn вернула ошибку, все сделанные через tx изменения откатываются
+type BatchStore[K comparable, T any] interface {
+	Batch(fn func(tx Store[K, T]) error) error
+}
+
+// Хранилище, которое умеет блокировать только данные нескольких вершин.
+// В fn атомарны лишь изменения вершин keys и дуг между ними
+type KeyedBatchStore[K comparable, T any] interface {
+	BatchKeys(keys []K, fn func(tx Store[K, T]) error) error
+}
+
+// Хранилище, которое даёт прочитать несколько значений согласованно.
+// Пока fn не вернётся, изменения хранилища ждут. Через view можно только читать
+type ViewStore[K comparable, T any] interface {
+	View(fn func(view Store[K, T]) error) error
+}
+
+// Запускает чтение fn согласованно, если хранилище это умеет
+func runView[K comparable, T any](s Store[K, T], fn func(view Store[K, T]) error) error {
+	if viewer, ok := s.(ViewStore[K, T]); ok {
+		return viewer.View(fn)
+	}
+
+	return fn(s)
+}
+
+// Граф, который даёт прочитать себя согласованно, как ViewStore
+type graphReader[K comparable, T any] interface {
+	readView(fn func(view Graph[K, T]) error) error
+}
+
+// Запускает чтение графа fn согласованно, если граф это умеет.
+// Иначе fn читает сам g, и между вызовами граф может измениться
+func readGraph[K comparable, T any](g Graph[K, T], fn func(view Graph[K, T]) error) error {
+	if reader, ok := g.(graphReader[K, T]); ok {
+		return reader.readView(fn)
+	}
+
+	return fn(g)
+}
+
+// Запускает fn атомарно, если хранилище это умеет.
+// Иначе изменения всё равно откатываются при ошибке, но без общей блокировки
+func runBatch[K comparable, T any](s Store[K, T], fn func(tx Store[K, T]) error) error {
+	if batcher, ok := s.(BatchStore[K, T]); ok {
+		return batcher.Batch(fn)
+	}
+
+	return runTx(s, fn)
+}
+
+// Как runBatch, но блокирует только вершины keys, если хранилище это умеет
+func runBatchKeys[K comparable, T any](s Store[K, T], keys []K, fn func(tx Store[K, T]) error) error {
+	if batcher, ok := s.(KeyedBatchStore[K, T]); ok {
+		return batcher.BatchKeys(keys, fn)
+	}
+
+	return runBatch(s, fn)
+}
+
+// Запускает fn и откатывает её изменения при ошибке
+func runTx[K comparable, T any](s Store[K, T], fn func(tx Store[K, T]) error) error {
+	tx := &txStore[K, T]{Store: s}
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.rollback(); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Хранилище, запоминающее обратное действие для каждого изменения
+type txStore[K comparable, T any] struct {
+	Store[K, T]
+	undo []func() error
+}
+
+func (t *txStore[K, T]) rollback() error {
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		if err := t.undo[i](); err != nil {
+			return err
+		}
+	}
+	t.undo = nil
+
+	return nil
+}
+
+func (t *txStore[K, T]) AddVertex(hash K, value T, properties VertexProperties) error {
+	if err := t.Store.AddVertex(hash, value, properties); err != nil {
+		return err
+	}
+
+	t.undo = append(t.undo, func() error {
+		return t.Store.RemoveVertex(hash)
+	})
+
+	return nil
+}
+
+func (t *txStore[K, T]) RemoveVertex(hash K) error {
+	value, properties, err := t.Store.Vertex(hash)
+	if err != nil {
+		return err
+	}
+
+	if err := t.Store.RemoveVertex(hash); err != nil {
+		return err
+	}
+
+	t.undo = append(t.undo, func() error {
+		return t.Store.AddVertex(hash, value, properties)
+	})
+
+	return nil
+}
+
+func (t *txStore[K, T]) EditVertex(hash K, properties VertexProperties) error {
+	_, previous, err := t.Store.Vertex(hash)
+	if err != nil {
+		return err
+	}
+
+	if err := t.Store.EditVertex(hash, properties); err != nil {
+		return err
+	}
+
+	t.undo = append(t.undo, func() error {
+		return t.Store.EditVertex(hash, previous)
+	})
+
+	return nil
+}
+
+func (t *txStore[K, T]) AddEdge(source, target K, edge Edge[K]) error {
+	// AddEdge перезаписывает существующую дугу, тогда откат её вернёт
+	previous, err := t.Store.Edge(source, target)
+	existed := err == nil
+
+	if err := t.Store.AddEdge(source, target, edge); err != nil {
+		return err
+	}
+
+	t.undo = append(t.undo, func() error {
+		if existed {
+			return t.Store.AddEdge(source, target, previous)
+		}
+		return t.Store.RemoveEdge(source, target)
+	})
+
+	return nil
+}
+
+func (t *txStore[K, T]) EditEdge(source, target K, edge Edge[K]) error {
+	previous, err := t.Store.Edge(source, target)
+	if err != nil {
+		return err
+	}
+
+	if err := t.Store.EditEdge(source, target, edge); err != nil {
+		return err
+	}
+
+	t.undo = append(t.undo, func() error {
+		return t.Store.EditEdge(source, target, previous)
+	})
+
+	return nil
+}
+
+func (t *txStore[K, T]) RemoveEdge(source, target K) error {
+	previous, err := t.Store.Edge(source, target)
+	existed := err == nil
+
+	// В мультиграфе вместе с парой удаляются и параллельные дуги
+	var parallel []Edge[K]
+	if multi, ok := t.Store.(MultiEdgeStore[K, T]); ok && existed {
+		edges, err := multi.EdgesBetween(source, target)
+		if err != nil {
+			return err
+		}
+		parallel = edges[1:]
+	}
+
+	if err := t.Store.RemoveEdge(source, target); err != nil {
+		return err
+	}
+
+	if existed {
+		t.undo = append(t.undo, func() error {
+			if err := t.Store.AddEdge(source, target, previous); err != nil {
+				return err
+			}
+
+			for _, edge := range parallel {
+				if err := t.Store.(MultiEdgeStore[K, T]).AddParallelEdge(source, target, edge); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return nil
+}
+
+// Дуги мультиграфа. Если хранилище их не умеет, методы возвращают ErrorMultigraphNotSupported
+
+func (t *txStore[K, T]) AddParallelEdge(source, target K, edge Edge[K]) error {
+	multi, err := multiEdges(t.Store)
+	if err != nil {
+		return err
+	}
+
+	if err := multi.AddParallelEdge(source, target, edge); err != nil {
+		return err
+	}
+
+	t.undo = append(t.undo, func() error {
+		return multi.RemoveParallelEdge(source, target, edge.Properties.ID)
+	})
+
+	return nil
+}
+
+func (t *txStore[K, T]) EditParallelEdge(source, target K, edge Edge[K]) error {
+	multi, err := multiEdges(t.Store)
+	if err != nil {
+		return err
+	}
+
+	previous, err := parallelEdge(multi.EdgesBetween, source, target, edge.Properties.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := multi.EditParallelEdge(source, target, edge); err != nil {
+		return err
+	}
+
+	t.undo = append(t.undo, func() error {
+		return multi.EditParallelEdge(source, target, previous)
+	})
+
+	return nil
+}
+
+func (t *txStore[K, T]) RemoveParallelEdge(source, target K, id uint64) error {
+	multi, err := multiEdges(t.Store)
+	if err != nil {
+		return err
+	}
+
+	previous, err := parallelEdge(multi.EdgesBetween, source, target, id)
+	if err != nil {
+		return err
+	}
+
+	if err := multi.RemoveParallelEdge(source, target, id); err != nil {
+		return err
+	}
+
+	t.undo = append(t.undo, func() error {
+		return multi.AddParallelEdge(source, target, previous)
+	})
+
+	return nil
+}
+
+func (t *txStore[K, T]) EdgesBetween(source, target K) ([]Edge[K], error) {
+	multi, err := multiEdges(t.Store)
+	if err != nil {
+		return nil, err
+	}
+
+	return multi.EdgesBetween(source, target)
+}
+
+func (t *txStore[K, T]) EdgeByID(id uint64) (Edge[K], error) {
+	multi, err := multiEdges(t.Store)
+	if err != nil {
+		return Edge[K]{}, err
+	}
+
+	return multi.EdgeByID(id)
+}
+
+// Дуга пары с данным ID
+func parallelEdge[K comparable](between func(source, target K) ([]Edge[K], error), source, target K, id uint64) (Edge[K], error) {
+	edges, err := between(source, target)
+	if err != nil {
+		return Edge[K]{}, err
+	}
+
+	for _, edge := range edges {
+		if edge.Properties.ID == id {
+			return edge, nil
+		}
+	}
+
+	return Edge[K]{}, ErrorEdgeNotFound
+}
@@ -1,21 +1,24 @@
 package graph
 
-func BFS[K comparable, T any](g Graph[K, T], start K, visit func(K) bool) error {
+import "errors"
+
+func BFS[K comparable, T any](g Graph[K, T], start K, visit func(K) bool, options ...func(*TraversalOptions)) error {
 	ignoreDepth := func(vertex K, _ int) bool {
 		return visit(vertex)
 	}
-	return BFSWithDepth(g, start, ignoreDepth)
+	return BFSWithDepth(g, start, ignoreDepth, options...)
 }
 
 // Функция обхода в ширину. Не рекурсивная. Использует очередь
 // Принимает в себя функцию как аргумент с ограничием глубины. Если аргумент пропустить
-func BFSWithDepth[K comparable, T any](g Graph[K, T], start K, visit func(K, int) bool) error {
-	adjacencyMap, err := g.AdjacencyMap()
-	if err != nil {
-		return err
-	}
-
-	if _, ok := adjacencyMap[start]; !ok {
+// Без вершины start обход ничего не посещает и возвращает nil
+func BFSWithDepth[K comparable, T any](g Graph[K, T], start K, visit func(K, int) bool, options ...func(*TraversalOptions)) error {
+	opts := traversalOptions(options)
+
+	// Соседей берём из хранилища по одной вершине, без копии всего графа
+	if _, err := g.Vertex(start); errors.Is(err, ErrorVertextNotFound) {
+		return nil
+	} else if err != nil {
 		return err
 	}
 
@@ -37,7 +40,12 @@ func BFSWithDepth[K comparable, T any](g Graph[K, T], start K, visit func(K, int
 			break
 		}
 
-		for adjacency := range adjacencyMap[current] {
+		neighbors, err := traversalNeighbors(g, current, opts)
+		if err != nil {
+			return err
+		}
+
+		for _, adjacency := range neighbors {
 			if _, ok := visited[adjacency]; !ok {
 				visited[adjacency] = true
 				queue = append(queue, adjacency)
@@ -0,0 +1,197 @@
+package graph
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// Ошибки, которые пишущие горутины получают законно: соседняя горутина успела раньше
+var concurrentErrors = []error{
+	ErrorVertexExists,
+	ErrorVertextNotFound,
+	ErrorEdgeExists,
+	ErrorEdgeNotFound,
+	ErrorVertexHashEdges,
+}
+
+func expectedConcurrentError(err error) bool {
+	if err == nil {
+		return true
+	}
+
+	for _, expected := range concurrentErrors {
+		if errors.Is(err, expected) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Запускать с -race: пишущие и читающие горутины работают с одним графом
+func TestConcurrentReadWrite(t *testing.T) {
+	stores := []struct {
+		name string
+		new  func() Store[int, int]
+	}{
+		{"memory", func() Store[int, int] { return NewMemoryStore[int, int]() }},
+		{"sharded", func() Store[int, int] { return NewShardedStore[int, int](4) }},
+	}
+
+	kinds := []struct {
+		name    string
+		options []func(*Traits)
+	}{
+		{"directed", []func(*Traits){Directed()}},
+		{"undirected", nil},
+	}
+
+	for _, store := range stores {
+		for _, kind := range kinds {
+			t.Run(store.name+"/"+kind.name, func(t *testing.T) {
+				g := NewWithStore(IntHash, store.new(), kind.options...)
+				stressGraph(t, g)
+				checkConsistent(t, g)
+			})
+		}
+	}
+}
+
+func stressGraph(t *testing.T, g Graph[int, int]) {
+	const (
+		vertices   = 32
+		writers    = 4
+		readers    = 4
+		iterations = 300
+	)
+
+	for i := 0; i < vertices; i++ {
+		if err := g.AddVertex(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	failures := make(chan error, writers+readers)
+
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			random := rand.New(rand.NewSource(seed))
+
+			for i := 0; i < iterations; i++ {
+				u, v := random.Intn(vertices), random.Intn(vertices)
+
+				var err error
+				switch random.Intn(6) {
+				case 0:
+					err = g.AddVertex(u)
+				case 1:
+					err = g.AddEdge(u, v, EdgeWeight(i))
+				case 2:
+					err = g.EditEdge(u, v, EdgeWeight(-i))
+				case 3:
+					err = g.RemoveEdge(u, v)
+				case 4:
+					err = g.RemoveVertex(u)
+				case 5:
+					err = g.Batch(func(tx Graph[int, int]) error {
+						if err := tx.AddEdge(u, v); err != nil {
+							return err
+						}
+						return tx.AddEdge(v, u)
+					})
+				}
+
+				if !expectedConcurrentError(err) {
+					failures <- err
+					return
+				}
+			}
+		}(int64(w))
+	}
+
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			random := rand.New(rand.NewSource(seed))
+
+			for i := 0; i < iterations; i++ {
+				u, v := random.Intn(vertices), random.Intn(vertices)
+
+				var err error
+				switch random.Intn(4) {
+				case 0:
+					_, err = g.AdjacencyMap()
+				case 1:
+					_, err = g.Edge(u, v)
+				case 2:
+					_, err = g.Degree(u)
+				case 3:
+					_, err = g.OutEdges(u)
+				}
+
+				if !expectedConcurrentError(err) {
+					failures <- err
+					return
+				}
+			}
+		}(int64(100 + r))
+	}
+
+	wg.Wait()
+	close(failures)
+
+	for err := range failures {
+		t.Error(err)
+	}
+}
+
+// После гонки список дуг, карта смежности и степени должны сходиться
+func checkConsistent(t *testing.T, g Graph[int, int]) {
+	t.Helper()
+
+	adjacency, err := g.AdjacencyMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edges, err := g.Edges()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size, err := g.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != len(edges) {
+		t.Fatalf("Size() = %d, а дуг %d", size, len(edges))
+	}
+
+	for _, edge := range edges {
+		if _, ok := adjacency[edge.Source][edge.Target]; !ok {
+			t.Fatalf("дуги %d -> %d нет в карте смежности", edge.Source, edge.Target)
+		}
+
+		if !g.Traits().IsDirected {
+			if _, ok := adjacency[edge.Target][edge.Source]; !ok {
+				t.Fatalf("у дуги %d - %d нет второй половины", edge.Source, edge.Target)
+			}
+		}
+	}
+
+	for hash, targets := range adjacency {
+		degree, err := g.OutDegree(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if degree != len(targets) {
+			t.Fatalf("OutDegree(%d) = %d, а в карте смежности %d", hash, degree, len(targets))
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package graph
+
+// Функции для направленных ациклических графов
+
+// Топологическая сортировка алгоритмом Кана.
+// Возвращает ErrorHasCycle, если в графе есть цикл
+func TopologicalSort[K comparable, T any](g Graph[K, T]) ([]K, error) {
+	if !g.Traits().IsDirected {
+		return nil, ErrorNotDirected
+	}
+
+	adjacencyMap, err := g.AdjacencyMap()
+	if err != nil {
+		return nil, err
+	}
+
+	inDegree := make(map[K]int, len(adjacencyMap))
+	for vertex := range adjacencyMap {
+		inDegree[vertex] = 0
+	}
+	for _, adjacencies := range adjacencyMap {
+		for adjacency := range adjacencies {
+			inDegree[adjacency]++
+		}
+	}
+
+	queue := make([]K, 0)
+	for vertex, degree := range inDegree {
+		if degree == 0 {
+			queue = append(queue, vertex)
+		}
+	}
+
+	order := make([]K, 0, len(adjacencyMap))
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		order = append(order, current)
+
+		for adjacency := range adjacencyMap[current] {
+			inDegree[adjacency]--
+			if inDegree[adjacency] == 0 {
+				queue = append(queue, adjacency)
+			}
+		}
+	}
+
+	// Вершины цикла так и не получили нулевую степень захода
+	if len(order) != len(adjacencyMap) {
+		return nil, ErrorHasCycle
+	}
+
+	return order, nil
+}
+
+// Транзитивное замыкание: дуга u -> v есть, если из u достижима v.
+// Существующие дуги сохраняют свои свойства, новые создаются пустыми
+func TransitiveClosure[K comparable, T any](g Graph[K, T]) (Graph[K, T], error) {
+	if !g.Traits().IsDirected {
+		return nil, ErrorNotDirected
+	}
+
+	closure, err := newDerived(g)
+	if err != nil {
+		return nil, err
+	}
+
+	adjacencyMap, err := g.AdjacencyMap()
+	if err != nil {
+		return nil, err
+	}
+
+	for source := range adjacencyMap {
+		// Обход начинается с соседей, чтобы петля появлялась только при цикле через source
+		visited := make(map[K]bool)
+		queue := make([]K, 0, len(adjacencyMap[source]))
+		for adjacency := range adjacencyMap[source] {
+			visited[adjacency] = true
+			queue = append(queue, adjacency)
+		}
+
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+
+			for adjacency := range adjacencyMap[current] {
+				if !visited[adjacency] {
+					visited[adjacency] = true
+					queue = append(queue, adjacency)
+				}
+			}
+		}
+
+		for target := range visited {
+			if edge, ok := adjacencyMap[source][target]; ok {
+				err = closure.AddEdge(copyEdge(edge))
+			} else {
+				err = closure.AddEdge(source, target)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return closure, nil
+}
+
+// Транзитивное сокращение ациклического графа: удаляются дуги u -> v,
+// если v достижима из u другим, более длинным путём.
+// Оставшиеся дуги сохраняют свои свойства
+func TransitiveReduction[K comparable, T any](g Graph[K, T]) (Graph[K, T], error) {
+	order, err := TopologicalSort(g)
+	if err != nil {
+		return nil, err
+	}
+
+	reduction, err := newDerived(g)
+	if err != nil {
+		return nil, err
+	}
+
+	adjacencyMap, err := g.AdjacencyMap()
+	if err != nil {
+		return nil, err
+	}
+
+	// Потомки каждой вершины. Считаем с конца топологического порядка,
+	// так что потомки детей уже посчитаны
+	descendants := make(map[K]map[K]struct{}, len(order))
+	for i := len(order) - 1; i >= 0; i-- {
+		vertex := order[i]
+		descendants[vertex] = make(map[K]struct{})
+
+		for adjacency := range adjacencyMap[vertex] {
+			descendants[vertex][adjacency] = struct{}{}
+			for descendant := range descendants[adjacency] {
+				descendants[vertex][descendant] = struct{}{}
+			}
+		}
+	}
+
+	for _, adjacencies := range adjacencyMap {
+		for target, edge := range adjacencies {
+			redundant := false
+
+			for other := range adjacencies {
+				if other == target {
+					continue
+				}
+				if _, ok := descendants[other][target]; ok {
+					redundant = true
+					break
+				}
+			}
+
+			if redundant {
+				continue
+			}
+
+			if err := reduction.AddEdge(copyEdge(edge)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return reduction, nil
+}
+
+// Новый граф того же вида с копией всех вершин, но без дуг.
+// Ограничение формы дерева снимается, потому что результат им может не быть
+func newDerived[K comparable, T any](g Graph[K, T]) (Graph[K, T], error) {
+	derived := NewLike(g)
+	derived.Traits().IsTree = false
+
+	if err := derived.AddVerticesFrom(g); err != nil {
+		return nil, err
+	}
+
+	return derived, nil
+}
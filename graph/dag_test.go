@@ -0,0 +1,171 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func directedGraph(t *testing.T, vertices int, edges [][2]int) Graph[int, int] {
+	t.Helper()
+
+	g := New(IntHash, Directed())
+	for i := 1; i <= vertices; i++ {
+		if err := g.AddVertex(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, edge := range edges {
+		if err := g.AddEdge(edge[0], edge[1], EdgeWeight(10*edge[0]+edge[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return g
+}
+
+// Дуги графа по порядку
+func edgeList(t *testing.T, g Graph[int, int]) [][2]int {
+	t.Helper()
+
+	edges, err := g.Edges()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := make([][2]int, 0, len(edges))
+	for _, edge := range edges {
+		res = append(res, [2]int{edge.Source, edge.Target})
+	}
+
+	slices.SortFunc(res, func(a, b [2]int) int {
+		return slices.Compare(a[:], b[:])
+	})
+
+	return res
+}
+
+var (
+	// Ромб 1 -> {2, 3} -> 4, лишняя дуга 1 -> 4 и хвост 4 -> 5
+	dagEdges = [][2]int{{1, 2}, {1, 3}, {2, 4}, {3, 4}, {1, 4}, {4, 5}}
+	// Цикл 2 -> 3 -> 4 -> 2 за вершиной 1
+	cycleEdges = [][2]int{{1, 2}, {2, 3}, {3, 4}, {4, 2}}
+)
+
+func TestTopologicalSort(t *testing.T) {
+	g := directedGraph(t, 6, dagEdges)
+
+	order, err := TopologicalSort(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(order) != 6 {
+		t.Fatalf("TopologicalSort = %v", order)
+	}
+
+	for _, edge := range dagEdges {
+		if slices.Index(order, edge[0]) > slices.Index(order, edge[1]) {
+			t.Fatalf("TopologicalSort = %v: %d после %d", order, edge[0], edge[1])
+		}
+	}
+}
+
+func TestTopologicalSortErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		g    Graph[int, int]
+		err  error
+	}{
+		{"цикл", directedGraph(t, 4, cycleEdges), ErrorHasCycle},
+		{"петля", directedGraph(t, 2, [][2]int{{1, 2}, {2, 2}}), ErrorHasCycle},
+		{"ненаправленный", New(IntHash), ErrorNotDirected},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := TopologicalSort(test.g); !errors.Is(err, test.err) {
+				t.Fatalf("ошибка %v, ожидалась %v", err, test.err)
+			}
+			if _, err := TransitiveReduction(test.g); !errors.Is(err, test.err) {
+				t.Fatalf("TransitiveReduction, ошибка %v, ожидалась %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestTransitiveClosure(t *testing.T) {
+	tests := []struct {
+		name     string
+		vertices int
+		edges    [][2]int
+		want     [][2]int
+	}{
+		{"ромб", 6, dagEdges, [][2]int{{1, 2}, {1, 3}, {1, 4}, {1, 5}, {2, 4}, {2, 5}, {3, 4}, {3, 5}, {4, 5}}},
+		// Петли появляются только у вершин цикла
+		{"цикл", 4, cycleEdges, [][2]int{{1, 2}, {1, 3}, {1, 4}, {2, 2}, {2, 3}, {2, 4}, {3, 2}, {3, 3}, {3, 4}, {4, 2}, {4, 3}, {4, 4}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := directedGraph(t, test.vertices, test.edges)
+
+			closure, err := TransitiveClosure(g)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := edgeList(t, closure); !slices.Equal(got, test.want) {
+				t.Fatalf("TransitiveClosure = %v, ожидалось %v", got, test.want)
+			}
+
+			if order, _ := closure.Order(); order != test.vertices {
+				t.Fatalf("в замыкании %d вершин", order)
+			}
+
+			// Старые дуги со своими свойствами, новые пустые
+			for _, pair := range test.want {
+				edge, err := closure.Edge(pair[0], pair[1])
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				want := 0
+				if slices.Contains(test.edges, pair) {
+					want = 10*pair[0] + pair[1]
+				}
+				if edge.Properties.Weight != want {
+					t.Fatalf("вес дуги %v = %d, ожидался %d", pair, edge.Properties.Weight, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTransitiveReduction(t *testing.T) {
+	g := directedGraph(t, 6, append(slices.Clone(dagEdges), [2]int{2, 5}, [2]int{1, 5}))
+
+	reduction, err := TransitiveReduction(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][2]int{{1, 2}, {1, 3}, {2, 4}, {3, 4}, {4, 5}}
+	if got := edgeList(t, reduction); !slices.Equal(got, want) {
+		t.Fatalf("TransitiveReduction = %v, ожидалось %v", got, want)
+	}
+
+	// Вершина без дуг остаётся, свойства дуг сохраняются
+	if order, _ := reduction.Order(); order != 6 {
+		t.Fatalf("в сокращении %d вершин", order)
+	}
+	if edge, _ := reduction.Edge(4, 5); edge.Properties.Weight != 45 {
+		t.Fatalf("вес дуги 4 -> 5 = %d", edge.Properties.Weight)
+	}
+
+	// Исходный граф не меняется
+	if size, _ := g.Size(); size != 8 {
+		t.Fatalf("в исходном графе %d дуг", size)
+	}
+}
@@ -0,0 +1,118 @@
+package graph
+
+import "sort"
+
+// Функции для работы со степенями вершин
+
+// Последовательность степеней вершин по невозрастанию
+func DegreeSequence[K comparable, T any](g Graph[K, T]) ([]int, error) {
+	adjacencyMap, err := g.AdjacencyMap()
+	if err != nil {
+		return nil, err
+	}
+
+	sequence := make([]int, 0, len(adjacencyMap))
+	for vertex := range adjacencyMap {
+		degree, err := g.Degree(vertex)
+		if err != nil {
+			return nil, err
+		}
+		sequence = append(sequence, degree)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(sequence)))
+
+	return sequence, nil
+}
+
+// Гистограмма степеней: степень -> количество вершин с такой степенью
+func DegreeHistogram[K comparable, T any](g Graph[K, T]) (map[int]int, error) {
+	sequence, err := DegreeSequence(g)
+	if err != nil {
+		return nil, err
+	}
+
+	histogram := make(map[int]int)
+	for _, degree := range sequence {
+		histogram[degree]++
+	}
+
+	return histogram, nil
+}
+
+// Проверка Гавела-Хакими: существует ли простой ненаправленный граф
+// с такой последовательностью степеней
+func IsGraphical(sequence []int) bool {
+	_, err := havelHakimi(sequence)
+	return err == nil
+}
+
+// Строит простой ненаправленный граф с вершинами 0..n-1,
+// где у вершины i степень sequence[i]
+func FromDegreeSequence(sequence []int, options ...func(*Traits)) (Graph[int, int], error) {
+	edges, err := havelHakimi(sequence)
+	if err != nil {
+		return nil, err
+	}
+
+	g := New(IntHash, options...)
+	if g.Traits().IsDirected {
+		return nil, ErrorNotUndirected
+	}
+
+	for i := range sequence {
+		if err := g.AddVertex(i); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, edge := range edges {
+		if err := g.AddEdge(edge[0], edge[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// Алгоритм Гавела-Хакими. Берём вершину с наибольшей остаточной степенью d
+// и соединяем её со следующими d вершинами по убыванию степени.
+// Возвращает список дуг между номерами вершин
+func havelHakimi(sequence []int) ([][2]int, error) {
+	type vertex struct {
+		index, degree int
+	}
+
+	vertices := make([]vertex, 0, len(sequence))
+	for i, degree := range sequence {
+		if degree < 0 {
+			return nil, ErrorNotGraphical
+		}
+		vertices = append(vertices, vertex{index: i, degree: degree})
+	}
+
+	edges := make([][2]int, 0)
+
+	for len(vertices) > 0 {
+		sort.SliceStable(vertices, func(i, j int) bool {
+			return vertices[i].degree > vertices[j].degree
+		})
+
+		current := vertices[0]
+		vertices = vertices[1:]
+
+		if current.degree > len(vertices) {
+			return nil, ErrorNotGraphical
+		}
+
+		for i := 0; i < current.degree; i++ {
+			if vertices[i].degree == 0 {
+				return nil, ErrorNotGraphical
+			}
+			vertices[i].degree--
+			edges = append(edges, [2]int{current.index, vertices[i].index})
+		}
+	}
+
+	return edges, nil
+}
@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestFromDegreeSequence(t *testing.T) {
+	tests := []struct {
+		sequence  []int
+		graphical bool
+	}{
+		{[]int{}, true},
+		{[]int{0, 0, 0}, true},
+		{[]int{1, 1}, true},
+		{[]int{2, 2, 2}, true},
+		{[]int{3, 3, 3, 3}, true},
+		{[]int{3, 3, 2, 2, 2}, true},
+		{[]int{1, 2, 3, 2, 1, 1, 3, 1, 4}, true},
+		// Нечётная сумма
+		{[]int{1}, false},
+		{[]int{3, 1, 1}, false},
+		// Степень больше числа остальных вершин
+		{[]int{2, 2}, false},
+		{[]int{4, 1, 1, 1}, false},
+		// Сумма чётная, но соседей с ненулевой степенью не хватает
+		{[]int{3, 3, 1, 1}, false},
+		{[]int{2, 0, 0}, false},
+		{[]int{-1, 1}, false},
+	}
+
+	for _, test := range tests {
+		if got := IsGraphical(test.sequence); got != test.graphical {
+			t.Fatalf("IsGraphical(%v) = %v", test.sequence, got)
+		}
+
+		g, err := FromDegreeSequence(test.sequence)
+		if !test.graphical {
+			if !errors.Is(err, ErrorNotGraphical) {
+				t.Fatalf("FromDegreeSequence(%v), ошибка %v", test.sequence, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("FromDegreeSequence(%v): %v", test.sequence, err)
+		}
+
+		// Простой граф: без петель, а повторное ребро AddEdge и так бы отклонил
+		edges, _ := g.Edges()
+		for _, edge := range edges {
+			if edge.Source == edge.Target {
+				t.Fatalf("FromDegreeSequence(%v) дал петлю у %d", test.sequence, edge.Source)
+			}
+		}
+
+		for i, want := range test.sequence {
+			if degree, _ := g.Degree(i); degree != want {
+				t.Fatalf("FromDegreeSequence(%v): степень %d равна %d", test.sequence, i, degree)
+			}
+		}
+
+		sequence, err := DegreeSequence(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := slices.Clone(test.sequence)
+		sort.Sort(sort.Reverse(sort.IntSlice(want)))
+		if !slices.Equal(sequence, want) {
+			t.Fatalf("DegreeSequence = %v, ожидалось %v", sequence, want)
+		}
+	}
+}
+
+func TestFromDegreeSequenceDirected(t *testing.T) {
+	if _, err := FromDegreeSequence([]int{1, 1}, Directed()); !errors.Is(err, ErrorNotUndirected) {
+		t.Fatalf("направленный граф, ошибка %v", err)
+	}
+}
+
+func TestDegreeHistogram(t *testing.T) {
+	g, err := FromDegreeSequence([]int{3, 3, 2, 2, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	histogram, err := DegreeHistogram(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := map[int]int{3: 2, 2: 3}; !maps.Equal(histogram, want) {
+		t.Fatalf("DegreeHistogram = %v, ожидалось %v", histogram, want)
+	}
+}
@@ -0,0 +1,412 @@
+package graph
+
+import (
+	"slices"
+	"sync"
+)
+
+// Хранилище для графов с целыми ключами 0..n, например с IntHash.
+// Вместо карт вершины лежат в срезах по номеру, а дуги в списках смежности,
+// отсортированных по номеру соседа, так что дуга ищется двоичным поиском.
+// Ключ растит срезы до своего номера, поэтому отрицательные ключи и ключи
+// больше DenseIntStoreOptions.MaxKey отклоняются с ErrorVertexKeyRange
+type DenseIntStore[T any] struct {
+	lock sync.RWMutex
+
+	maxKey int
+
+	present    []bool
+	values     []T
+	properties []VertexProperties
+	count      int
+
+	// Исходящие дуги хранят свойства, входящие только номер источника.
+	// Оба списка упорядочены по номеру соседа
+	out [][]denseEdge
+	in  [][]int
+}
+
+type denseEdge struct {
+	target     int
+	properties EdgeProperties
+}
+
+// Наибольший ключ по умолчанию: срезы на столько вершин занимают сотни мегабайт
+const denseDefaultMaxKey = 1<<22 - 1
+
+type DenseIntStoreOptions struct {
+	// Наибольший допустимый ключ вершины
+	MaxKey int
+}
+
+// Свой предел ключей вершин
+func DenseMaxKey(max int) func(*DenseIntStoreOptions) {
+	return func(o *DenseIntStoreOptions) {
+		o.MaxKey = max
+	}
+}
+
+func NewDenseIntStore[T any](options ...func(*DenseIntStoreOptions)) *DenseIntStore[T] {
+	opts := DenseIntStoreOptions{
+		MaxKey: denseDefaultMaxKey,
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &DenseIntStore[T]{maxKey: opts.MaxKey}
+}
+
+// Граф поверх DenseIntStore с пределом ключей по умолчанию.
+// Другой предел задаётся через NewWithStore и DenseMaxKey
+func NewDenseInt[T any](hash Hash[int, T], options ...func(*Traits)) Graph[int, T] {
+	return NewWithStore[int, T](hash, NewDenseIntStore[T](), options...)
+}
+
+func (s *DenseIntStore[T]) NewEmpty() Store[int, T] {
+	return NewDenseIntStore[T](DenseMaxKey(s.maxKey))
+}
+
+// Блокирует хранилище на всё время fn. Срезы могут вырасти внутри fn,
+// поэтому после неё забираем их обратно из вида
+func (s *DenseIntStore[T]) Batch(fn func(tx Store[int, T]) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	view := &DenseIntStore[T]{
+		maxKey:     s.maxKey,
+		present:    s.present,
+		values:     s.values,
+		properties: s.properties,
+		count:      s.count,
+		out:        s.out,
+		in:         s.in,
+	}
+
+	err := runTx[int, T](view, fn)
+
+	s.present = view.present
+	s.values = view.values
+	s.properties = view.properties
+	s.count = view.count
+	s.out = view.out
+	s.in = view.in
+
+	return err
+}
+
+func (s *DenseIntStore[T]) View(fn func(view Store[int, T]) error) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return fn(&DenseIntStore[T]{
+		maxKey:     s.maxKey,
+		present:    s.present,
+		values:     s.values,
+		properties: s.properties,
+		count:      s.count,
+		out:        s.out,
+		in:         s.in,
+	})
+}
+
+func (s *DenseIntStore[T]) has(hash int) bool {
+	return hash >= 0 && hash < len(s.present) && s.present[hash]
+}
+
+// Растит срезы так, чтобы в них поместился номер hash
+func (s *DenseIntStore[T]) grow(hash int) {
+	if hash < len(s.present) {
+		return
+	}
+
+	size := 2 * len(s.present)
+	if size <= hash {
+		size = hash + 1
+	}
+	// Удвоение не должно выходить за предел ключей
+	if size > s.maxKey+1 {
+		size = s.maxKey + 1
+	}
+
+	s.present = append(s.present, make([]bool, size-len(s.present))...)
+	s.values = append(s.values, make([]T, size-len(s.values))...)
+	s.properties = append(s.properties, make([]VertexProperties, size-len(s.properties))...)
+	s.out = append(s.out, make([][]denseEdge, size-len(s.out))...)
+	s.in = append(s.in, make([][]int, size-len(s.in))...)
+}
+
+func (s *DenseIntStore[T]) AddVertex(hash int, value T, properties VertexProperties) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if hash < 0 || hash > s.maxKey {
+		return ErrorVertexKeyRange
+	}
+
+	if s.has(hash) {
+		return ErrorVertexExists
+	}
+
+	s.grow(hash)
+	s.present[hash] = true
+	s.values[hash] = value
+	s.properties[hash] = properties
+	s.count++
+
+	return nil
+}
+
+func (s *DenseIntStore[T]) Vertex(hash int) (T, VertexProperties, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if !s.has(hash) {
+		var empty T
+		return empty, VertexProperties{}, ErrorVertextNotFound
+	}
+
+	return s.values[hash], s.properties[hash], nil
+}
+
+func (s *DenseIntStore[T]) RemoveVertex(hash int) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.has(hash) {
+		return ErrorVertextNotFound
+	}
+
+	if len(s.out[hash]) > 0 || len(s.in[hash]) > 0 {
+		return ErrorVertexHashEdges
+	}
+
+	var empty T
+	s.present[hash] = false
+	s.values[hash] = empty
+	s.properties[hash] = VertexProperties{}
+	s.out[hash] = nil
+	s.in[hash] = nil
+	s.count--
+
+	return nil
+}
+
+func (s *DenseIntStore[T]) EditVertex(hash int, properties VertexProperties) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.has(hash) {
+		return ErrorVertextNotFound
+	}
+
+	s.properties[hash] = properties
+
+	return nil
+}
+
+func (s *DenseIntStore[T]) ListVertices() ([]int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	res := make([]int, 0, s.count)
+	for hash, ok := range s.present {
+		if ok {
+			res = append(res, hash)
+		}
+	}
+
+	return res, nil
+}
+
+func (s *DenseIntStore[T]) VertexCount() (int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.count, nil
+}
+
+// Номер дуги source -> target в списке source или -1
+func (s *DenseIntStore[T]) find(source, target int) int {
+	if source < 0 || source >= len(s.out) {
+		return -1
+	}
+
+	i, ok := slices.BinarySearchFunc(s.out[source], target, compareDenseTarget)
+	if !ok {
+		return -1
+	}
+
+	return i
+}
+
+func compareDenseTarget(edge denseEdge, target int) int {
+	return edge.target - target
+}
+
+func (s *DenseIntStore[T]) AddEdge(source, target int, edge Edge[int]) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.has(source) || !s.has(target) {
+		return ErrorVertextNotFound
+	}
+
+	// Как и в MemoryStore, повторная дуга перезаписывается
+	if i := s.find(source, target); i >= 0 {
+		s.out[source][i].properties = edge.Properties
+		return nil
+	}
+
+	i, _ := slices.BinarySearchFunc(s.out[source], target, compareDenseTarget)
+	s.out[source] = slices.Insert(s.out[source], i, denseEdge{target: target, properties: edge.Properties})
+
+	j, _ := slices.BinarySearch(s.in[target], source)
+	s.in[target] = slices.Insert(s.in[target], j, source)
+
+	return nil
+}
+
+func (s *DenseIntStore[T]) EditEdge(source, target int, edge Edge[int]) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	i := s.find(source, target)
+	if i < 0 {
+		return ErrorEdgeNotFound
+	}
+
+	s.out[source][i].properties = edge.Properties
+
+	return nil
+}
+
+func (s *DenseIntStore[T]) RemoveEdge(source, target int) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	i := s.find(source, target)
+	if i < 0 {
+		return nil
+	}
+
+	s.out[source] = slices.Delete(s.out[source], i, i+1)
+
+	if j, ok := slices.BinarySearch(s.in[target], source); ok {
+		s.in[target] = slices.Delete(s.in[target], j, j+1)
+	}
+
+	return nil
+}
+
+func (s *DenseIntStore[T]) Edge(source, target int) (Edge[int], error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	i := s.find(source, target)
+	if i < 0 {
+		return Edge[int]{}, ErrorEdgeNotFound
+	}
+
+	return Edge[int]{Source: source, Target: target, Properties: s.out[source][i].properties}, nil
+}
+
+func (s *DenseIntStore[T]) ListEdges() ([]Edge[int], error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	res := make([]Edge[int], 0)
+	for source, edges := range s.out {
+		for _, edge := range edges {
+			res = append(res, Edge[int]{Source: source, Target: edge.target, Properties: edge.properties})
+		}
+	}
+
+	return res, nil
+}
+
+func (s *DenseIntStore[T]) InDegree(hash int) (int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if !s.has(hash) {
+		return 0, ErrorVertextNotFound
+	}
+
+	return len(s.in[hash]), nil
+}
+
+func (s *DenseIntStore[T]) OutDegree(hash int) (int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if !s.has(hash) {
+		return 0, ErrorVertextNotFound
+	}
+
+	return len(s.out[hash]), nil
+}
+
+func (s *DenseIntStore[T]) Neighbors(hash int) ([]int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if !s.has(hash) {
+		return nil, ErrorVertextNotFound
+	}
+
+	res := make([]int, 0, len(s.out[hash]))
+	for _, edge := range s.out[hash] {
+		res = append(res, edge.target)
+	}
+
+	return res, nil
+}
+
+func (s *DenseIntStore[T]) Predecessors(hash int) ([]int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if !s.has(hash) {
+		return nil, ErrorVertextNotFound
+	}
+
+	res := make([]int, len(s.in[hash]))
+	copy(res, s.in[hash])
+
+	return res, nil
+}
+
+func (s *DenseIntStore[T]) OutEdges(hash int) ([]Edge[int], error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if !s.has(hash) {
+		return nil, ErrorVertextNotFound
+	}
+
+	res := make([]Edge[int], 0, len(s.out[hash]))
+	for _, edge := range s.out[hash] {
+		res = append(res, Edge[int]{Source: hash, Target: edge.target, Properties: edge.properties})
+	}
+
+	return res, nil
+}
+
+func (s *DenseIntStore[T]) InEdges(hash int) ([]Edge[int], error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if !s.has(hash) {
+		return nil, ErrorVertextNotFound
+	}
+
+	res := make([]Edge[int], 0, len(s.in[hash]))
+	for _, source := range s.in[hash] {
+		i := s.find(source, hash)
+		res = append(res, Edge[int]{Source: source, Target: hash, Properties: s.out[source][i].properties})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,193 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestDenseIntStoreKeyRange(t *testing.T) {
+	g := NewDenseInt(IntHash, Directed())
+
+	if err := g.AddVertex(1 << 40); !errors.Is(err, ErrorVertexKeyRange) {
+		t.Fatalf("AddVertex(1 << 40) вернул %v", err)
+	}
+	if err := g.AddVertex(-1); !errors.Is(err, ErrorVertexKeyRange) {
+		t.Fatalf("AddVertex(-1) вернул %v", err)
+	}
+
+	small := NewWithStore[int, int](IntHash, NewDenseIntStore[int](DenseMaxKey(10)), Directed())
+
+	if err := small.AddVertex(10); err != nil {
+		t.Fatal(err)
+	}
+	if err := small.AddVertex(11); !errors.Is(err, ErrorVertexKeyRange) {
+		t.Fatalf("AddVertex(11) при пределе 10 вернул %v", err)
+	}
+
+	// Удвоение срезов не выходит за предел
+	store := NewDenseIntStore[int](DenseMaxKey(10))
+	for i := 0; i <= 10; i++ {
+		if err := store.AddVertex(i, i, VertexProperties{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(store.present) != 11 {
+		t.Fatalf("срезы выросли до %d при пределе 10", len(store.present))
+	}
+}
+
+// Случайные добавления и удаления дуг дают то же, что и в MemoryStore,
+// а списки смежности остаются упорядоченными
+func TestDenseIntStoreMatchesMemory(t *testing.T) {
+	const n = 20
+
+	dense := NewDenseIntStore[int]()
+	memory := NewMemoryStore[int, int]()
+	stores := []Store[int, int]{dense, memory}
+
+	for _, s := range stores {
+		for i := 0; i < n; i++ {
+			if err := s.AddVertex(i, i, VertexProperties{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		source, target := random.Intn(n), random.Intn(n)
+		edge := Edge[int]{Source: source, Target: target, Properties: EdgeProperties{Weight: i, Attributes: map[string]string{}}}
+		remove := random.Intn(3) == 0
+
+		for _, s := range stores {
+			var err error
+			if remove {
+				err = s.RemoveEdge(source, target)
+			} else {
+				err = s.AddEdge(source, target, edge)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if got, want := storeState[int, int](t, dense), storeState[int, int](t, memory); got != want {
+		t.Fatalf("DenseIntStore:\n%s\nMemoryStore:\n%s", got, want)
+	}
+
+	for v := 0; v < n; v++ {
+		if !slices.IsSortedFunc(dense.out[v], func(a, b denseEdge) int { return a.target - b.target }) || !slices.IsSorted(dense.in[v]) {
+			t.Fatalf("списки вершины %d не упорядочены: %v %v", v, dense.out[v], dense.in[v])
+		}
+
+		for _, source := range dense.in[v] {
+			if _, err := dense.Edge(source, v); err != nil {
+				t.Fatalf("входящая дуга %d -> %d не находится: %v", source, v, err)
+			}
+		}
+	}
+}
+
+// Хранилища с целыми ключами для сравнения
+var denseBenchmarkStores = []struct {
+	name string
+	new  func() Store[int, int]
+}{
+	{"dense", func() Store[int, int] { return NewDenseIntStore[int]() }},
+	{"memory", func() Store[int, int] { return NewMemoryStore[int, int]() }},
+}
+
+// Граф из n вершин, у каждой degree исходящих дуг
+func denseBenchmarkGraph(b *testing.B, store Store[int, int], n, degree int) Graph[int, int] {
+	g := NewWithStore(IntHash, store, Directed())
+
+	for i := 0; i < n; i++ {
+		if err := g.AddVertex(i); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		for j := 1; j <= degree; j++ {
+			if err := g.AddEdge(i, (i+j)%n); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	return g
+}
+
+// Память на построение графа: B/op и allocs/op за весь граф
+func BenchmarkDenseIntStoreBuild(b *testing.B) {
+	for _, store := range denseBenchmarkStores {
+		for _, n := range []int{1000, 10000} {
+			b.Run(fmt.Sprintf("%s/%d", store.name, n), func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					denseBenchmarkGraph(b, store.new(), n, 8)
+				}
+			})
+		}
+	}
+}
+
+// Обход соседей всех вершин
+func BenchmarkDenseIntStoreNeighbors(b *testing.B) {
+	const n = 10000
+
+	for _, store := range denseBenchmarkStores {
+		g := denseBenchmarkGraph(b, store.new(), n, 8)
+
+		b.Run(store.name+"/Neighbors", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				for v := 0; v < n; v++ {
+					if _, err := g.Neighbors(v); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+
+		b.Run(store.name+"/OutEdges", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				for v := 0; v < n; v++ {
+					if _, err := g.OutEdges(v); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+// Поиск дуги у вершины с большой степенью
+func BenchmarkDenseIntStoreEdge(b *testing.B) {
+	const n = 10000
+
+	for _, store := range denseBenchmarkStores {
+		g := denseBenchmarkGraph(b, store.new(), n, 0)
+		for v := 1; v < n; v++ {
+			if err := g.AddEdge(0, v); err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		b.Run(store.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := g.Edge(0, 1+i%(n-1)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -1,15 +1,16 @@
 package graph
 
+import "errors"
+
 // Функция обхода в глубину. Использует не рекурсивный метод через кучу
-func DFS[K comparable, T any](g Graph[K, T], start K, visit func(K) bool) error {
-	// Строит карту смежности
-	adjacencyMap, err := g.AdjacencyMap()
-	if err != nil {
-		return err
-	}
+// Без вершины start обход ничего не посещает и возвращает nil
+func DFS[K comparable, T any](g Graph[K, T], start K, visit func(K) bool, options ...func(*TraversalOptions)) error {
+	opts := traversalOptions(options)
 
-	// Находим в карте смежности заданную нами вершину
-	if _, ok := adjacencyMap[start]; !ok {
+	// Проверяем что заданная нами вершина есть в графе
+	if _, err := g.Vertex(start); errors.Is(err, ErrorVertextNotFound) {
+		return nil
+	} else if err != nil {
 		return err
 	}
 
@@ -31,8 +32,14 @@ func DFS[K comparable, T any](g Graph[K, T], start K, visit func(K) bool) error
 			}
 			visited[current] = true
 
+			// Соседей читаем прямо из хранилища
+			neighbors, err := traversalNeighbors(g, current, opts)
+			if err != nil {
+				return err
+			}
+
 			// Кладем следущю вершину
-			for adjacency := range adjacencyMap[current] {
+			for _, adjacency := range neighbors {
 				stack.push(adjacency)
 			}
 		}
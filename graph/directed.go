@@ -8,6 +8,15 @@ type directed[K comparable, T any] struct {
 	hash   Hash[K, T]
 	traits *Traits
 	store  Store[K, T]
+	// Граф внутри Batch, хранилище уже заблокировано
+	batched bool
+
+	events *observers[K]
+	// События текущего Batch, уходят подписчикам после его успеха
+	pending *[]Event[K]
+
+	// ID дуг мультиграфа
+	ids *edgeCounter
 }
 
 func newDirected[K comparable, T any](hash Hash[K, T], traits *Traits, store Store[K, T]) *directed[K, T] {
@@ -15,6 +24,8 @@ func newDirected[K comparable, T any](hash Hash[K, T], traits *Traits, store Sto
 		hash:   hash,
 		traits: traits,
 		store:  store,
+		events: newObservers[K](),
+		ids:    newEdgeCounter(store, traits),
 	}
 }
 
@@ -23,6 +34,10 @@ func (d *directed[K, T]) Traits() *Traits {
 }
 
 func (d *directed[K, T]) AddVertex(value T, options ...func(*VertexProperties)) error {
+	if d.hash == nil {
+		return ErrorHashUnknown
+	}
+
 	hash := d.hash(value)
 	properties := VertexProperties{
 		Weight:     0,
@@ -32,8 +47,37 @@ func (d *directed[K, T]) AddVertex(value T, options ...func(*VertexProperties))
 	for _, option := range options {
 		option(&properties)
 	}
+	d.traits.syncVertexWeight(&properties)
+
+	// Корень и события обрабатываем под той же блокировкой, что и саму вершину
+	if (d.traits.IsRooted || d.events.active()) && !d.batched {
+		return d.atomic(func(tx *directed[K, T]) error {
+			return tx.addVertex(hash, value, properties)
+		})
+	}
 
-	return d.store.AddVertex(hash, value, properties)
+	return d.addVertex(hash, value, properties)
+}
+
+func (d *directed[K, T]) addVertex(hash K, value T, properties VertexProperties) error {
+	// Слушатели узнают только об изменении, которое точно применится
+	if d.events.active() {
+		if _, _, err := d.store.Vertex(hash); err == nil {
+			return ErrorVertexExists
+		}
+	}
+
+	event := Event[K]{Kind: VertexAdded, Vertex: hash, VertexProperties: properties}
+
+	return d.events.change(d.pending, event, func() error {
+		if err := d.store.AddVertex(hash, value, properties); err != nil {
+			return err
+		}
+
+		d.traits.recordRoot(hash)
+
+		return nil
+	})
 }
 
 func (d *directed[K, T]) AddVerticesFrom(g Graph[K, T]) error {
@@ -73,11 +117,161 @@ func (d *directed[K, T]) VertexWithProperties(hash K) (T, VertexProperties, erro
 
 // Удаляем вершину
 func (d *directed[K, T]) RemoveVertex(hash K) error {
-	return d.store.RemoveVertex(hash)
+	if (d.traits.IsRooted || d.events.active()) && !d.batched {
+		return d.atomic(func(tx *directed[K, T]) error {
+			return tx.RemoveVertex(hash)
+		})
+	}
+
+	event := Event[K]{Kind: VertexRemoved, Vertex: hash}
+
+	if d.events.active() {
+		_, properties, err := d.store.Vertex(hash)
+		if err != nil {
+			return err
+		}
+
+		degree, err := d.degree(hash)
+		if err != nil {
+			return err
+		}
+
+		if degree > 0 {
+			return ErrorVertexHashEdges
+		}
+
+		event.VertexProperties = properties
+	}
+
+	return d.events.change(d.pending, event, func() error {
+		if err := d.store.RemoveVertex(hash); err != nil {
+			return err
+		}
+
+		d.traits.forgetRoot(hash)
+
+		return nil
+	})
+}
+
+// Удаляет вершину и её дуги под одной блокировкой. Слушатели получают
+// отдельное событие на каждую дугу и затем на вершину
+func (d *directed[K, T]) RemoveVertexCascade(hash K) ([]Edge[K], error) {
+	var removed []Edge[K]
+
+	err := d.atomic(func(tx *directed[K, T]) error {
+		if _, _, err := tx.store.Vertex(hash); err != nil {
+			return err
+		}
+
+		out, err := tx.store.OutEdges(hash)
+		if err != nil {
+			return err
+		}
+
+		in, err := tx.store.InEdges(hash)
+		if err != nil {
+			return err
+		}
+
+		removed = make([]Edge[K], 0, len(out)+len(in))
+
+		for _, edge := range out {
+			if err := tx.removeListedEdge(edge); err != nil {
+				return err
+			}
+			removed = append(removed, edge)
+		}
+
+		for _, edge := range in {
+			// Петля уже удалена вместе с исходящими
+			if edge.Source == hash {
+				continue
+			}
+
+			if err := tx.removeListedEdge(edge); err != nil {
+				return err
+			}
+			removed = append(removed, edge)
+		}
+
+		return tx.RemoveVertex(hash)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return removed, nil
+}
+
+func (d *directed[K, T]) EditVertex(hash K, options ...func(*VertexProperties)) error {
+	// Чтение старых свойств и запись без вмешательства других горутин
+	return d.atomicPair(hash, hash, func(tx *directed[K, T]) error {
+		_, properties, err := tx.store.Vertex(hash)
+		if err != nil {
+			return err
+		}
+
+		before := properties
+
+		// Карта атрибутов общая с хранилищем, правим копию
+		properties.Attributes = copyAttributes(properties.Attributes)
+
+		for _, option := range options {
+			option(&properties)
+		}
+		tx.traits.syncVertexWeight(&properties)
+
+		event := Event[K]{Kind: VertexEdited, Vertex: hash, VertexProperties: properties, VertexBefore: before}
+
+		return tx.events.change(tx.pending, event, func() error {
+			return tx.store.EditVertex(hash, properties)
+		})
+	})
 }
 
 // Добавлеяем дугу
 func (d *directed[K, T]) AddEdge(source, target K, options ...func(*EdgeProperties)) error {
+	_, err := d.AddEdgeWithID(source, target, options...)
+	return err
+}
+
+func (d *directed[K, T]) AddEdgeWithID(source, target K, options ...func(*EdgeProperties)) (uint64, error) {
+	edge := Edge[K]{
+		Source: source,
+		Target: target,
+		Properties: EdgeProperties{
+			Attributes: make(map[string]string),
+		},
+	}
+
+	for _, option := range options {
+		option(&edge.Properties)
+	}
+	d.traits.syncEdgeWeight(&edge.Properties)
+
+	insert := func(tx *directed[K, T]) error {
+		return tx.insertEdge(&edge)
+	}
+
+	// Проверки и запись идут под одной блокировкой хранилища.
+	// Свой ID дуги проверяется по всему графу, тогда блокируется всё хранилище
+	var err error
+	if d.traits.IsMultigraph && edge.Properties.ID != 0 {
+		err = d.atomic(insert)
+	} else {
+		err = d.atomicPair(source, target, insert)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return edge.Properties.ID, nil
+}
+
+func (d *directed[K, T]) insertEdge(edge *Edge[K]) error {
+	source, target := edge.Source, edge.Target
+
 	// Две проверки на существование вершины
 	_, _, err := d.store.Vertex(source)
 	if err != nil {
@@ -89,23 +283,29 @@ func (d *directed[K, T]) AddEdge(source, target K, options ...func(*EdgeProperti
 		return err
 	}
 
-	if _, err := d.Edge(source, target); !errors.Is(err, ErrorEdgeNotFound) {
-		return ErrorEdgeExists
-	}
+	if d.traits.IsMultigraph {
+		if err := takeEdgeID(d.store, d.ids, &edge.Properties); err != nil {
+			return err
+		}
+	} else {
+		if _, err := d.Edge(source, target); !errors.Is(err, ErrorEdgeNotFound) {
+			return ErrorEdgeExists
+		}
 
-	edge := Edge[K]{
-		Source: source,
-		Target: target,
-		Properties: EdgeProperties{
-			Attributes: make(map[string]string),
-		},
+		edge.Properties.ID = 0
 	}
 
-	for _, option := range options {
-		option(&edge.Properties)
+	if d.traits.IsTree {
+		if err := d.checkTreeEdge(source, target); err != nil {
+			return err
+		}
 	}
 
-	return d.addEdge(source, target, edge)
+	event := Event[K]{Kind: EdgeAdded, Source: source, Target: target, After: edge.Properties}
+
+	return d.events.change(d.pending, event, func() error {
+		return d.addEdge(source, target, *edge)
+	})
 }
 
 func (d *directed[K, T]) AddEdgesFrom(g Graph[K, T]) error {
@@ -124,6 +324,19 @@ func (d *directed[K, T]) AddEdgesFrom(g Graph[K, T]) error {
 }
 
 func (d *directed[K, T]) Edge(source, target K) (Edge[T], error) {
+	var res Edge[T]
+
+	// Дугу и её вершины читаем согласованно
+	err := d.read(func(view *directed[K, T]) error {
+		var err error
+		res, err = view.edge(source, target)
+		return err
+	})
+
+	return res, err
+}
+
+func (d *directed[K, T]) edge(source, target K) (Edge[T], error) {
 	edge, err := d.store.Edge(source, target)
 	if err != nil {
 		return Edge[T]{}, err
@@ -143,9 +356,12 @@ func (d *directed[K, T]) Edge(source, target K) (Edge[T], error) {
 		Source: sourceVertex,
 		Target: targetVertex,
 		Properties: EdgeProperties{
-			Weight:     edge.Properties.Weight,
-			Attributes: edge.Properties.Attributes,
-			Data:       edge.Properties.Data,
+			Weight:      edge.Properties.Weight,
+			FloatWeight: edge.Properties.FloatWeight,
+			Attributes:  edge.Properties.Attributes,
+			Data:        edge.Properties.Data,
+			ID:          edge.Properties.ID,
+			Type:        edge.Properties.Type,
 		},
 	}, nil
 }
@@ -155,31 +371,77 @@ func (d *directed[K, T]) Edges() ([]Edge[K], error) {
 }
 
 func (d *directed[K, T]) EditEdge(source, target K, options ...func(properties *EdgeProperties)) error {
-	existingEdge, err := d.store.Edge(source, target)
-	if err != nil {
-		return err
-	}
+	// Чтение старых свойств и запись без вмешательства других горутин
+	return d.atomicPair(source, target, func(tx *directed[K, T]) error {
+		if tx.traits.IsMultigraph {
+			return tx.eachParallelEdge(source, target, func(edge Edge[K]) error {
+				return tx.editParallelEdge(edge, options)
+			})
+		}
 
-	for _, option := range options {
-		option(&existingEdge.Properties)
-	}
+		existingEdge, err := tx.store.Edge(source, target)
+		if err != nil {
+			return err
+		}
+
+		before := existingEdge.Properties
+
+		// Карта атрибутов общая с хранилищем, правим копию
+		existingEdge.Properties.Attributes = copyAttributes(existingEdge.Properties.Attributes)
 
-	return d.store.EditEdge(source, target, existingEdge)
+		for _, option := range options {
+			option(&existingEdge.Properties)
+		}
+		tx.traits.syncEdgeWeight(&existingEdge.Properties)
+
+		event := Event[K]{Kind: EdgeEdited, Source: source, Target: target, Before: before, After: existingEdge.Properties}
+
+		return tx.events.change(tx.pending, event, func() error {
+			return tx.store.EditEdge(source, target, existingEdge)
+		})
+	})
 }
 
 func (d *directed[K, T]) RemoveEdge(source, target K) error {
-	if _, err := d.Edge(source, target); err != nil {
-		return err
-	}
+	// Проверка и удаление под одной блокировкой хранилища
+	return d.atomicPair(source, target, func(tx *directed[K, T]) error {
+		if tx.traits.IsMultigraph {
+			return tx.eachParallelEdge(source, target, tx.removeParallelEdge)
+		}
 
-	if err := d.store.RemoveEdge(source, target); err != nil {
-		return err
-	}
+		existingEdge, err := tx.store.Edge(source, target)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		if tx.traits.IsTree {
+			if err := tx.checkTreeRemoval(target); err != nil {
+				return err
+			}
+		}
+
+		event := Event[K]{Kind: EdgeRemoved, Source: source, Target: target, Before: existingEdge.Properties}
+
+		return tx.events.change(tx.pending, event, func() error {
+			return tx.store.RemoveEdge(source, target)
+		})
+	})
 }
 
 func (d *directed[K, T]) AdjacencyMap() (map[K]map[K]Edge[K], error) {
+	var res map[K]map[K]Edge[K]
+
+	// Вершины и дуги читаем согласованно, иначе дуга может сослаться на вершину не из списка
+	err := d.read(func(view *directed[K, T]) error {
+		var err error
+		res, err = view.adjacencyMap()
+		return err
+	})
+
+	return res, err
+}
+
+func (d *directed[K, T]) adjacencyMap() (map[K]map[K]Edge[K], error) {
 	vertices, err := d.store.ListVertices()
 	if err != nil {
 		return nil, err
@@ -204,6 +466,19 @@ func (d *directed[K, T]) AdjacencyMap() (map[K]map[K]Edge[K], error) {
 }
 
 func (d *directed[K, T]) PredecessorMap() (map[K]map[K]Edge[K], error) {
+	var res map[K]map[K]Edge[K]
+
+	// Вершины и дуги читаем согласованно, иначе дуга может сослаться на вершину не из списка
+	err := d.read(func(view *directed[K, T]) error {
+		var err error
+		res, err = view.predecessorMap()
+		return err
+	})
+
+	return res, err
+}
+
+func (d *directed[K, T]) predecessorMap() (map[K]map[K]Edge[K], error) {
 	vertices, err := d.store.ListVertices()
 	if err != nil {
 		return nil, err
@@ -230,22 +505,352 @@ func (d *directed[K, T]) PredecessorMap() (map[K]map[K]Edge[K], error) {
 	return m, nil
 }
 
+// Дерево растёт от корня: новая дуга подвешивает вершину без родителя
+// к корню или к вершине, у которой родитель уже есть. Так у каждой вершины
+// с родителем путь вверх доходит до корня, и цикл не замкнуть.
+// Проверка смотрит только на степени source и target
+func (d *directed[K, T]) checkTreeEdge(source, target K) error {
+	root, rooted := d.traits.root.(K)
+	if rooted && root == target {
+		return ErrorEdgeBreaksTree
+	}
+
+	degree, err := d.store.InDegree(target)
+	if err != nil {
+		return err
+	}
+
+	if degree > 0 {
+		return ErrorEdgeBreaksTree
+	}
+
+	if rooted && root == source {
+		return nil
+	}
+
+	if degree, err = d.store.InDegree(source); err != nil {
+		return err
+	}
+
+	if degree == 0 {
+		return ErrorEdgeBreaksTree
+	}
+
+	return nil
+}
+
+// Удалить из дерева можно только дугу к листу, иначе
+// оторванное поддерево потеряет путь к корню
+func (d *directed[K, T]) checkTreeRemoval(target K) error {
+	children, err := d.store.OutDegree(target)
+	if err != nil {
+		return err
+	}
+
+	if children > 0 {
+		return ErrorEdgeBreaksTree
+	}
+
+	return nil
+}
+
 func (d *directed[K, T]) addEdge(source, target K, edge Edge[K]) error {
+	if d.traits.IsMultigraph {
+		multi, err := multiEdges(d.store)
+		if err != nil {
+			return err
+		}
+
+		return multi.AddParallelEdge(source, target, edge)
+	}
+
 	return d.store.AddEdge(source, target, edge)
 }
 
+func (d *directed[K, T]) EdgesBetween(source, target K) ([]Edge[K], error) {
+	if d.traits.IsMultigraph {
+		multi, err := multiEdges(d.store)
+		if err != nil {
+			return nil, err
+		}
+
+		return multi.EdgesBetween(source, target)
+	}
+
+	edge, err := d.store.Edge(source, target)
+	if errors.Is(err, ErrorEdgeNotFound) {
+		return []Edge[K]{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return []Edge[K]{edge}, nil
+}
+
+func (d *directed[K, T]) EdgeByID(id uint64) (Edge[K], error) {
+	if !d.traits.IsMultigraph {
+		return Edge[K]{}, ErrorNotMultigraph
+	}
+
+	multi, err := multiEdges(d.store)
+	if err != nil {
+		return Edge[K]{}, err
+	}
+
+	return multi.EdgeByID(id)
+}
+
+func (d *directed[K, T]) EditEdgeByID(id uint64, options ...func(properties *EdgeProperties)) error {
+	return d.withEdgeID(id, func(tx *directed[K, T], edge Edge[K]) error {
+		return tx.editParallelEdge(edge, options)
+	})
+}
+
+func (d *directed[K, T]) RemoveEdgeByID(id uint64) error {
+	return d.withEdgeID(id, func(tx *directed[K, T], edge Edge[K]) error {
+		return tx.removeParallelEdge(edge)
+	})
+}
+
+// Находит дугу по ID и вызывает fn под блокировкой её вершин
+func (d *directed[K, T]) withEdgeID(id uint64, fn func(tx *directed[K, T], edge Edge[K]) error) error {
+	edge, err := d.EdgeByID(id)
+	if err != nil {
+		return err
+	}
+
+	return d.atomicPair(edge.Source, edge.Target, func(tx *directed[K, T]) error {
+		multi, err := multiEdges(tx.store)
+		if err != nil {
+			return err
+		}
+
+		// До блокировки дугу могли изменить или удалить
+		current, err := parallelEdge(multi.EdgesBetween, edge.Source, edge.Target, id)
+		if err != nil {
+			return err
+		}
+
+		return fn(tx, current)
+	})
+}
+
+// Вызывает fn для каждой дуги пары. Пустая пара это ErrorEdgeNotFound
+func (d *directed[K, T]) eachParallelEdge(source, target K, fn func(edge Edge[K]) error) error {
+	edges, err := d.EdgesBetween(source, target)
+	if err != nil {
+		return err
+	}
+
+	if len(edges) == 0 {
+		return ErrorEdgeNotFound
+	}
+
+	for _, edge := range edges {
+		if err := fn(edge); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *directed[K, T]) editParallelEdge(edge Edge[K], options []func(properties *EdgeProperties)) error {
+	multi, err := multiEdges(d.store)
+	if err != nil {
+		return err
+	}
+
+	before := edge.Properties
+
+	// Карта атрибутов общая с хранилищем, правим копию
+	edge.Properties.Attributes = copyAttributes(edge.Properties.Attributes)
+
+	for _, option := range options {
+		option(&edge.Properties)
+	}
+	d.traits.syncEdgeWeight(&edge.Properties)
+
+	// ID дуги не меняется
+	edge.Properties.ID = before.ID
+
+	event := Event[K]{Kind: EdgeEdited, Source: edge.Source, Target: edge.Target, Before: before, After: edge.Properties}
+
+	return d.events.change(d.pending, event, func() error {
+		return multi.EditParallelEdge(edge.Source, edge.Target, edge)
+	})
+}
+
+func (d *directed[K, T]) removeParallelEdge(edge Edge[K]) error {
+	multi, err := multiEdges(d.store)
+	if err != nil {
+		return err
+	}
+
+	if d.traits.IsTree {
+		if err := d.checkTreeRemoval(edge.Target); err != nil {
+			return err
+		}
+	}
+
+	event := Event[K]{Kind: EdgeRemoved, Source: edge.Source, Target: edge.Target, Before: edge.Properties}
+
+	return d.events.change(d.pending, event, func() error {
+		return multi.RemoveParallelEdge(edge.Source, edge.Target, edge.Properties.ID)
+	})
+}
+
+// Удаляет дугу из списка хранилища. В мультиграфе только её, а не всю пару
+func (d *directed[K, T]) removeListedEdge(edge Edge[K]) error {
+	if d.traits.IsMultigraph {
+		return d.removeParallelEdge(edge)
+	}
+
+	return d.RemoveEdge(edge.Source, edge.Target)
+}
+
+func (d *directed[K, T]) Neighbors(hash K) ([]K, error) {
+	return d.store.Neighbors(hash)
+}
+
+func (d *directed[K, T]) Predecessors(hash K) ([]K, error) {
+	return d.store.Predecessors(hash)
+}
+
+func (d *directed[K, T]) OutEdges(hash K) ([]Edge[K], error) {
+	return d.store.OutEdges(hash)
+}
+
+func (d *directed[K, T]) InEdges(hash K) ([]Edge[K], error) {
+	return d.store.InEdges(hash)
+}
+
+// Выполняет группу изменений атомарно: хранилище блокируется один раз,
+// а при ошибке fn все изменения откатываются
+func (d *directed[K, T]) Batch(fn func(tx Graph[K, T]) error) error {
+	return d.atomic(func(tx *directed[K, T]) error {
+		return fn(tx)
+	})
+}
+
+// Выполняет чтение fn над согласованным видом хранилища.
+// Внутри Batch хранилище уже заблокировано, поэтому просто вызывает fn
+func (d *directed[K, T]) read(fn func(view *directed[K, T]) error) error {
+	if d.batched {
+		return fn(d)
+	}
+
+	return runView(d.store, func(store Store[K, T]) error {
+		return fn(d.tx(store))
+	})
+}
+
+func (d *directed[K, T]) readView(fn func(view Graph[K, T]) error) error {
+	return d.read(func(view *directed[K, T]) error {
+		return fn(view)
+	})
+}
+
+// Как atomic, но блокирует только вершины source и target, если хранилище это умеет
+func (d *directed[K, T]) atomicPair(source, target K, fn func(tx *directed[K, T]) error) error {
+	if d.batched {
+		return d.atomic(fn)
+	}
+
+	return runBatchKeys(d.store, []K{source, target}, d.commit(fn))
+}
+
+// Выполняет fn над графом поверх заблокированного хранилища.
+// Внутри уже идущего Batch просто вызывает fn
+func (d *directed[K, T]) atomic(fn func(tx *directed[K, T]) error) error {
+	if d.batched {
+		return fn(d)
+	}
+
+	return runBatch(d.store, d.commit(fn))
+}
+
+// Тело транзакции над заблокированным хранилищем.
+// События подписчикам уходят только после успеха fn, пока хранилище ещё заблокировано,
+// поэтому подписчики видят изменения в том же порядке, в каком они применились
+func (d *directed[K, T]) commit(fn func(tx *directed[K, T]) error) func(store Store[K, T]) error {
+	return func(store Store[K, T]) error {
+		tx := d.tx(store)
+		tx.pending = new([]Event[K])
+
+		// Корень мог запомниться на вершине, которая откатится
+		var root any
+		if tx.traits.IsRooted {
+			root = tx.traits.root
+		}
+
+		if err := fn(tx); err != nil {
+			if tx.traits.IsRooted {
+				tx.traits.root = root
+			}
+			return err
+		}
+
+		d.events.publish(*tx.pending)
+
+		return nil
+	}
+}
+
+// Граф поверх уже заблокированного хранилища
+func (d *directed[K, T]) tx(store Store[K, T]) *directed[K, T] {
+	return &directed[K, T]{
+		hash:    d.hash,
+		traits:  d.traits,
+		store:   store,
+		batched: true,
+		events:  d.events,
+		pending: d.pending,
+		ids:     d.ids,
+	}
+}
+
+func (d *directed[K, T]) Observe(listener Listener[K]) func() {
+	return d.events.observe(listener)
+}
+
+func (d *directed[K, T]) Subscribe(buffer int) (<-chan Event[K], func()) {
+	return d.events.subscribe(buffer)
+}
+
+func (d *directed[K, T]) Snapshot() (*Snapshot[K, T], error) {
+	store, release, err := snapshotStore(d.store)
+	if err != nil {
+		return nil, err
+	}
+
+	// Корень может смениться, поэтому у снимка свои свойства
+	traits := *d.traits
+
+	return newSnapshot[K, T](newDirected(d.hash, &traits, store), release), nil
+}
+
+func (d *directed[K, T]) hashFunc() Hash[K, T] {
+	return d.hash
+}
+
+func (d *directed[K, T]) storage() Store[K, T] {
+	return d.store
+}
+
 func (d *directed[K, T]) Clone() (Graph[K, T], error) {
 	traits := &Traits{
-		IsDirected: d.traits.IsDirected,
-		IsWeighted: d.traits.IsWeighted,
-		IsRooted:   d.traits.IsRooted,
+		IsDirected:      d.traits.IsDirected,
+		IsWeighted:      d.traits.IsWeighted,
+		IsFloatWeighted: d.traits.IsFloatWeighted,
+		IsRooted:        d.traits.IsRooted,
+		IsTree:          d.traits.IsTree,
+		IsMultigraph:    d.traits.IsMultigraph,
+		root:            currentRoot[K, T](d),
 	}
 
-	clone := &directed[K, T]{
-		hash:   d.hash,
-		traits: traits,
-		store:  newStore[K, T](),
-	}
+	clone := newDirected(d.hash, traits, emptyLike(d.store))
 
 	if err := clone.AddVerticesFrom(d); err != nil {
 		return nil, err
@@ -263,17 +868,42 @@ func (d *directed[K, T]) Order() (int, error) {
 }
 
 func (d *directed[K, T]) Size() (int, error) {
-	size := 0
-	outEdges, err := d.AdjacencyMap()
+	return edgeCount[K, T](d.store)
+}
+
+func (d *directed[K, T]) InDegree(hash K) (int, error) {
+	return d.store.InDegree(hash)
+}
+
+func (d *directed[K, T]) OutDegree(hash K) (int, error) {
+	return d.store.OutDegree(hash)
+}
+
+func (d *directed[K, T]) Degree(hash K) (int, error) {
+	var res int
+
+	// Обе полустепени читаем согласованно
+	err := d.read(func(view *directed[K, T]) error {
+		var err error
+		res, err = view.degree(hash)
+		return err
+	})
+
+	return res, err
+}
+
+func (d *directed[K, T]) degree(hash K) (int, error) {
+	in, err := d.store.InDegree(hash)
 	if err != nil {
 		return 0, err
 	}
 
-	for _, outEdges := range outEdges {
-		size += len(outEdges)
+	out, err := d.store.OutDegree(hash)
+	if err != nil {
+		return 0, err
 	}
 
-	return size, nil
+	return in + out, nil
 }
 
 func copyEdge[K comparable](edge Edge[K]) (K, K, func(properties *EdgeProperties)) {
@@ -282,7 +912,10 @@ func copyEdge[K comparable](edge Edge[K]) (K, K, func(properties *EdgeProperties
 			p.Attributes[k] = v
 		}
 		p.Weight = edge.Properties.Weight
+		p.FloatWeight = edge.Properties.FloatWeight
 		p.Data = edge.Properties.Data
+		p.ID = edge.Properties.ID
+		p.Type = edge.Properties.Type
 	}
 
 	return edge.Source, edge.Target, copyProperties
@@ -0,0 +1,142 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// Граф для каскадного удаления вершины 2: петля, дуги в обе стороны
+// и в мультиграфе параллельные дуги. Дуга 4 - 1 с вершиной 2 не связана
+func cascadeGraph(t *testing.T, options ...func(*Traits)) (Graph[int, int], []uint64) {
+	t.Helper()
+
+	g := New(IntHash, options...)
+	for i := 1; i <= 4; i++ {
+		if err := g.AddVertex(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	edges := [][2]int{{1, 2}, {2, 2}, {2, 3}, {4, 1}}
+	if g.Traits().IsDirected {
+		edges = append(edges, [2]int{3, 2})
+	}
+	if g.Traits().IsMultigraph {
+		edges = append(edges, [2]int{1, 2}, [2]int{2, 2})
+	}
+
+	var ids []uint64
+	for _, edge := range edges {
+		id, err := g.AddEdgeWithID(edge[0], edge[1], EdgeWeight(10*edge[0]+edge[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if edge[0] == 2 || edge[1] == 2 {
+			ids = append(ids, id)
+		}
+	}
+
+	return g, ids
+}
+
+// Проверяет граф после RemoveVertexCascade(2): осталась только дуга 4 - 1
+func checkCascade(t *testing.T, g Graph[int, int], removed []Edge[int], ids []uint64) {
+	t.Helper()
+
+	if len(removed) != len(ids) {
+		t.Fatalf("удалено %d дуг, ожидалось %d: %v", len(removed), len(ids), removed)
+	}
+	for _, edge := range removed {
+		if edge.Source != 2 && edge.Target != 2 {
+			t.Fatalf("удалена чужая дуга %d - %d", edge.Source, edge.Target)
+		}
+		if edge.Properties.Weight != 10*edge.Source+edge.Target && edge.Properties.Weight != 10*edge.Target+edge.Source {
+			t.Fatalf("у удалённой дуги %d - %d вес %d", edge.Source, edge.Target, edge.Properties.Weight)
+		}
+	}
+
+	if _, err := g.Vertex(2); !errors.Is(err, ErrorVertextNotFound) {
+		t.Fatalf("вершина 2 осталась: %v", err)
+	}
+
+	edges, err := g.Edges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) != 1 || edges[0].Properties.Weight != 41 {
+		t.Fatalf("остались дуги %v, ожидалась только 4 - 1", edges)
+	}
+	if size, _ := g.Size(); size != 1 {
+		t.Fatalf("Size() = %d, ожидалось 1", size)
+	}
+
+	for _, v := range []int{1, 3} {
+		neighbors, err := g.Neighbors(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if slices.Contains(neighbors, 2) {
+			t.Fatalf("у вершины %d остался сосед 2", v)
+		}
+	}
+	if degree, _ := g.Degree(3); degree != 0 {
+		t.Fatalf("Degree(3) = %d, ожидалось 0", degree)
+	}
+
+	if g.Traits().IsMultigraph {
+		for _, id := range ids {
+			if _, err := g.EdgeByID(id); !errors.Is(err, ErrorEdgeNotFound) {
+				t.Fatalf("дуга %d осталась: %v", id, err)
+			}
+		}
+	}
+
+	// Ключ свободен для новой вершины
+	if err := g.AddVertex(2); err != nil {
+		t.Fatal(err)
+	}
+	if degree, _ := g.Degree(2); degree != 0 {
+		t.Fatalf("у новой вершины 2 степень %d", degree)
+	}
+}
+
+func TestDirectedRemoveVertexCascade(t *testing.T) {
+	for _, kind := range []struct {
+		name    string
+		options []func(*Traits)
+	}{
+		{"simple", []func(*Traits){Directed()}},
+		{"multigraph", []func(*Traits){Directed(), Multigraph()}},
+	} {
+		t.Run(kind.name, func(t *testing.T) {
+			g, ids := cascadeGraph(t, kind.options...)
+
+			removed, err := g.RemoveVertexCascade(2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkCascade(t, g, removed, ids)
+
+			if degree, _ := g.OutDegree(3); degree != 0 {
+				t.Fatalf("OutDegree(3) = %d, ожидалось 0", degree)
+			}
+			if degree, _ := g.InDegree(1); degree != 1 {
+				t.Fatalf("InDegree(1) = %d, ожидалось 1", degree)
+			}
+		})
+	}
+}
+
+func TestRemoveVertexCascadeMissing(t *testing.T) {
+	for _, options := range [][]func(*Traits){{Directed()}, nil} {
+		g, _ := cascadeGraph(t, options...)
+
+		if _, err := g.RemoveVertexCascade(9); !errors.Is(err, ErrorVertextNotFound) {
+			t.Fatalf("RemoveVertexCascade(9) вернул %v", err)
+		}
+		if size, _ := g.Size(); size == 0 {
+			t.Fatal("неудачный каскад удалил дуги")
+		}
+	}
+}
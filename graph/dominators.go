@@ -0,0 +1,268 @@
+package graph
+
+// Дерево доминаторов для направленного корневого графа.
+// Вершина a доминирует над b, если любой путь из корня в b проходит через a.
+// Считается алгоритмом Купера-Харви-Кеннеди: итеративно по обратному пост-порядку
+
+type Dominators[K comparable] struct {
+	root K
+	// Непосредственный доминатор вершины. Для корня это сам корень
+	idom map[K]K
+	// Номер вершины в пост-порядке обхода из корня
+	post map[K]int
+	// Предшественники в том графе, по которому строилось дерево
+	preds map[K][]K
+}
+
+// Строит дерево доминаторов от корня, записанного в графе через Rooted
+func DominatorTree[K comparable, T any](g Graph[K, T]) (*Dominators[K], error) {
+	root, err := Root(g)
+	if err != nil {
+		return nil, err
+	}
+
+	return DominatorTreeFrom(g, root)
+}
+
+// Строит дерево доминаторов от заданной вершины
+func DominatorTreeFrom[K comparable, T any](g Graph[K, T], root K) (*Dominators[K], error) {
+	succs, preds, err := dominatorInput(g, root)
+	if err != nil {
+		return nil, err
+	}
+
+	return computeDominators(root, succs, preds), nil
+}
+
+// Строит дерево постдоминаторов: то же самое, но на развёрнутом графе от выхода.
+// Вершина a постдоминирует над b, если любой путь из b в exit проходит через a
+func PostDominatorTree[K comparable, T any](g Graph[K, T], exit K) (*Dominators[K], error) {
+	succs, preds, err := dominatorInput(g, exit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Меняем направление всех дуг
+	return computeDominators(exit, preds, succs), nil
+}
+
+// Корень дерева
+func (d *Dominators[K]) Root() K {
+	return d.root
+}
+
+// Возвращает непосредственного доминатора вершины.
+// false для корня и для вершин, недостижимых из корня
+func (d *Dominators[K]) ImmediateDominator(v K) (K, bool) {
+	idom, ok := d.idom[v]
+	if !ok || v == d.root {
+		var empty K
+		return empty, false
+	}
+
+	return idom, true
+}
+
+// Проверяет, доминирует ли a над b. Каждая вершина доминирует сама над собой
+func (d *Dominators[K]) Dominates(a, b K) bool {
+	if _, ok := d.idom[b]; !ok {
+		return false
+	}
+
+	for {
+		if a == b {
+			return true
+		}
+		if b == d.root {
+			return false
+		}
+		b = d.idom[b]
+	}
+}
+
+// Возвращает дерево доминаторов в виде карты: вершина -> её дети
+func (d *Dominators[K]) Tree() map[K][]K {
+	tree := make(map[K][]K, len(d.idom))
+
+	for v := range d.idom {
+		tree[v] = make([]K, 0)
+	}
+
+	for v, idom := range d.idom {
+		if v == d.root {
+			continue
+		}
+		tree[idom] = append(tree[idom], v)
+	}
+
+	return tree
+}
+
+// Возвращает границы доминирования для всех достижимых вершин
+func (d *Dominators[K]) Frontier() map[K][]K {
+	frontier := make(map[K]map[K]struct{}, len(d.idom))
+	for v := range d.idom {
+		frontier[v] = make(map[K]struct{})
+	}
+
+	for b := range d.idom {
+		preds := d.reachablePreds(b)
+		if len(preds) < 2 {
+			continue
+		}
+
+		for _, p := range preds {
+			runner := p
+			for runner != d.idom[b] {
+				frontier[runner][b] = struct{}{}
+				if runner == d.root {
+					break
+				}
+				runner = d.idom[runner]
+			}
+		}
+	}
+
+	res := make(map[K][]K, len(frontier))
+	for v, set := range frontier {
+		res[v] = make([]K, 0, len(set))
+		for w := range set {
+			res[v] = append(res[v], w)
+		}
+	}
+
+	return res
+}
+
+func (d *Dominators[K]) reachablePreds(v K) []K {
+	res := make([]K, 0, len(d.preds[v]))
+	for _, p := range d.preds[v] {
+		if _, ok := d.post[p]; ok {
+			res = append(res, p)
+		}
+	}
+
+	return res
+}
+
+// Собирает списки последователей и предшественников для направленного графа
+func dominatorInput[K comparable, T any](g Graph[K, T], root K) (map[K][]K, map[K][]K, error) {
+	if !g.Traits().IsDirected {
+		return nil, nil, ErrorNotDirected
+	}
+
+	adjacencyMap, err := g.AdjacencyMap()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, ok := adjacencyMap[root]; !ok {
+		return nil, nil, ErrorVertextNotFound
+	}
+
+	succs := make(map[K][]K, len(adjacencyMap))
+	preds := make(map[K][]K, len(adjacencyMap))
+
+	for source, adjacencies := range adjacencyMap {
+		for target := range adjacencies {
+			succs[source] = append(succs[source], target)
+			preds[target] = append(preds[target], source)
+		}
+	}
+
+	return succs, preds, nil
+}
+
+func computeDominators[K comparable](root K, succs, preds map[K][]K) *Dominators[K] {
+	d := &Dominators[K]{
+		root:  root,
+		idom:  make(map[K]K),
+		post:  make(map[K]int),
+		preds: preds,
+	}
+
+	order := postOrder(root, succs, d.post)
+
+	d.idom[root] = root
+
+	intersect := func(a, b K) K {
+		for a != b {
+			for d.post[a] < d.post[b] {
+				a = d.idom[a]
+			}
+			for d.post[b] < d.post[a] {
+				b = d.idom[b]
+			}
+		}
+		return a
+	}
+
+	// Идём в обратном пост-порядке, пока значения не перестанут меняться
+	for changed := true; changed; {
+		changed = false
+
+		for i := len(order) - 1; i >= 0; i-- {
+			b := order[i]
+			if b == root {
+				continue
+			}
+
+			var newIdom K
+			found := false
+
+			for _, p := range preds[b] {
+				if _, ok := d.idom[p]; !ok {
+					continue
+				}
+
+				if !found {
+					newIdom = p
+					found = true
+					continue
+				}
+
+				newIdom = intersect(p, newIdom)
+			}
+
+			if current, ok := d.idom[b]; found && (!ok || current != newIdom) {
+				d.idom[b] = newIdom
+				changed = true
+			}
+		}
+	}
+
+	return d
+}
+
+// Не рекурсивный пост-порядок обхода из root. Заполняет номера в post
+func postOrder[K comparable](root K, succs map[K][]K, post map[K]int) []K {
+	type frame struct {
+		vertex K
+		next   int
+	}
+
+	order := make([]K, 0, len(succs))
+	visited := map[K]bool{root: true}
+	frames := []frame{{vertex: root}}
+
+	for len(frames) > 0 {
+		top := &frames[len(frames)-1]
+
+		if top.next < len(succs[top.vertex]) {
+			next := succs[top.vertex][top.next]
+			top.next++
+
+			if !visited[next] {
+				visited[next] = true
+				frames = append(frames, frame{vertex: next})
+			}
+			continue
+		}
+
+		post[top.vertex] = len(order)
+		order = append(order, top.vertex)
+		frames = frames[:len(frames)-1]
+	}
+
+	return order
+}
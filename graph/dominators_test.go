@@ -0,0 +1,164 @@
+package graph
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// Граф потока управления:
+//
+//	1 -> 2, ромб 2 -> 3 -> 5 и 2 -> 4 -> 5, цикл 5 -> 2, выход 5 -> 6.
+//	Вершина 7 недостижима из входа, но ведёт в 5
+func controlFlowGraph(t *testing.T) Graph[int, int] {
+	t.Helper()
+
+	g := New(IntHash, Directed(), Rooted())
+	for i := 1; i <= 7; i++ {
+		if err := g.AddVertex(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, edge := range [][2]int{{1, 2}, {2, 3}, {2, 4}, {3, 5}, {4, 5}, {5, 2}, {5, 6}, {7, 5}} {
+		if err := g.AddEdge(edge[0], edge[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return g
+}
+
+// Списки в картах без порядка, сравниваем отсортированными
+func sortedLists(lists map[int][]int) map[int][]int {
+	for _, list := range lists {
+		slices.Sort(list)
+	}
+
+	return lists
+}
+
+func TestDominatorTree(t *testing.T) {
+	g := controlFlowGraph(t)
+
+	// Корень это первая добавленная вершина
+	dominators, err := DominatorTree(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dominators.Root() != 1 {
+		t.Fatalf("корень %d, ожидался 1", dominators.Root())
+	}
+
+	idoms := map[int]int{2: 1, 3: 2, 4: 2, 5: 2, 6: 5}
+	for v := 1; v <= 7; v++ {
+		idom, ok := dominators.ImmediateDominator(v)
+		want, wantOK := idoms[v]
+		if idom != want || ok != wantOK {
+			t.Fatalf("ImmediateDominator(%d) = %d, %v, ожидалось %d, %v", v, idom, ok, want, wantOK)
+		}
+	}
+
+	tests := []struct {
+		a, b int
+		want bool
+	}{
+		{1, 6, true},
+		{2, 5, true},
+		{5, 5, true},
+		// Ни одна из веток ромба не доминирует над слиянием
+		{3, 5, false},
+		{4, 6, false},
+		// Из-за цикла 5 -> 2 вершина 5 не становится доминатором 2
+		{5, 2, false},
+		// Недостижимой вершиной не доминирует никто
+		{1, 7, false},
+		{7, 7, false},
+	}
+	for _, test := range tests {
+		if got := dominators.Dominates(test.a, test.b); got != test.want {
+			t.Fatalf("Dominates(%d, %d) = %v, ожидалось %v", test.a, test.b, got, test.want)
+		}
+	}
+
+	tree := map[int][]int{1: {2}, 2: {3, 4, 5}, 3: {}, 4: {}, 5: {6}, 6: {}}
+	if got := sortedLists(dominators.Tree()); !maps.EqualFunc(got, tree, slices.Equal) {
+		t.Fatalf("Tree() = %v, ожидалось %v", got, tree)
+	}
+}
+
+func TestDominanceFrontier(t *testing.T) {
+	dominators, err := DominatorTree(controlFlowGraph(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Граница слияния ромба на 5, у 5 и заголовка цикла 2 граница на 2.
+	// Дуга 7 -> 5 из недостижимой вершины не учитывается
+	want := map[int][]int{1: {}, 2: {2}, 3: {5}, 4: {5}, 5: {2}, 6: {}}
+	if got := sortedLists(dominators.Frontier()); !maps.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("Frontier() = %v, ожидалось %v", got, want)
+	}
+}
+
+func TestPostDominatorTree(t *testing.T) {
+	postDominators, err := PostDominatorTree(controlFlowGraph(t), 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Из 7 выход тоже достижим, только через 5
+	ipdoms := map[int]int{1: 2, 2: 5, 3: 5, 4: 5, 5: 6, 7: 5}
+	for v := 1; v <= 7; v++ {
+		ipdom, ok := postDominators.ImmediateDominator(v)
+		want, wantOK := ipdoms[v]
+		if ipdom != want || ok != wantOK {
+			t.Fatalf("ImmediateDominator(%d) = %d, %v, ожидалось %d, %v", v, ipdom, ok, want, wantOK)
+		}
+	}
+
+	if !postDominators.Dominates(5, 1) || postDominators.Dominates(3, 2) {
+		t.Fatal("неверные постдоминаторы ромба")
+	}
+}
+
+func TestDominatorTreeErrors(t *testing.T) {
+	if _, err := DominatorTree(New(IntHash, Directed())); !errors.Is(err, ErrorNotRooted) {
+		t.Fatalf("граф без корня, ошибка %v", err)
+	}
+
+	undirected := New(IntHash, Rooted())
+	_ = undirected.AddVertex(1)
+	if _, err := DominatorTree(undirected); !errors.Is(err, ErrorNotDirected) {
+		t.Fatalf("ненаправленный граф, ошибка %v", err)
+	}
+
+	if _, err := DominatorTreeFrom(controlFlowGraph(t), 100); !errors.Is(err, ErrorVertextNotFound) {
+		t.Fatalf("нет корня в графе, ошибка %v", err)
+	}
+}
+
+// Корень задаётся опцией или первой вершиной и меняется только вместе с графом
+func TestGraphRoot(t *testing.T) {
+	g := New(IntHash, Directed(), RootedAt(2))
+	for i := 1; i <= 3; i++ {
+		_ = g.AddVertex(i)
+	}
+
+	if root, err := Root(g); err != nil || root != 2 {
+		t.Fatalf("Root = %d, %v, ожидался 2", root, err)
+	}
+
+	// Новый корень это следующая добавленная вершина
+	if err := g.RemoveVertex(2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Root(g); !errors.Is(err, ErrorNotRooted) {
+		t.Fatalf("Root после удаления корня, ошибка %v", err)
+	}
+	_ = g.AddVertex(4)
+	if root, err := Root(g); err != nil || root != 4 {
+		t.Fatalf("Root = %d, %v, ожидался 4", root, err)
+	}
+}
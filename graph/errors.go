@@ -8,6 +8,34 @@ var (
 	ErrorVertextNotFound = errors.New("Вершина не найден")
 	ErrorEdgeExists      = errors.New("Дуг уже существует")
 	ErrorEdgeNotFound    = errors.New("Дуга не найдена")
+	ErrorEdgeDataType    = errors.New("Данные дуги не того типа")
 
 	ErrorVertexHashEdges = errors.New("У вершины ещё есть дуги")
+	ErrorVertexKeyRange  = errors.New("Ключ вершины вне допустимого диапазона")
+
+	ErrorGraphReadOnly = errors.New("Граф доступен только для чтения")
+	ErrorHashUnknown   = errors.New("Хэш-функция графа неизвестна")
+
+	ErrorNotRooted     = errors.New("У графа не задан корень")
+	ErrorNotDirected   = errors.New("Граф не направленный")
+	ErrorNotUndirected = errors.New("Граф направленный")
+	ErrorNotTree       = errors.New("Граф не является деревом")
+	ErrorHasCycle      = errors.New("В графе есть цикл")
+	ErrorNotMultigraph = errors.New("Граф не мультиграф")
+
+	ErrorEdgeBreaksTree = errors.New("Дуга нарушает форму дерева")
+	ErrorNotGraphical   = errors.New("Последовательность степеней не реализуется графом")
+
+	ErrorMultigraphNotSupported = errors.New("Хранилище не поддерживает параллельные дуги")
+	ErrorIndexNotFound          = errors.New("Индекс не объявлен")
+
+	ErrorWeightMissing      = errors.New("У дуги нет атрибута с весом")
+	ErrorWeightNotNumber    = errors.New("Вес дуги не является числом")
+	ErrorWeightDataType     = errors.New("Данные дуги другого типа")
+	ErrorNegativeWeight     = errors.New("Отрицательный вес дуги")
+	ErrorTargetNotReachable = errors.New("Вершина недостижима")
+
+	ErrorNothingToUndo      = errors.New("Нет изменений для отмены")
+	ErrorNothingToRedo      = errors.New("Нет изменений для повтора")
+	ErrorCheckpointNotFound = errors.New("Точка истории не найдена")
 )
@@ -0,0 +1,222 @@
+package graph
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Вид изменения графа
+type EventKind int
+
+const (
+	VertexAdded EventKind = iota
+	VertexRemoved
+	EdgeAdded
+	EdgeEdited
+	EdgeRemoved
+	VertexEdited
+)
+
+// Изменение графа.
+// Для событий вершин заполнены Vertex и VertexProperties, для событий дуг Source, Target,
+// Before и After. У добавленной дуги пустой Before, у удалённой пустой After.
+// У VertexEdited в VertexProperties новые свойства, а в VertexBefore старые
+type Event[K comparable] struct {
+	Kind EventKind
+
+	Vertex           K
+	VertexProperties VertexProperties
+	VertexBefore     VertexProperties
+
+	Source K
+	Target K
+	Before EdgeProperties
+	After  EdgeProperties
+}
+
+// Слушатель изменений. Ненулевая ошибка запрещает изменение,
+// и изменяющий метод графа возвращает эту ошибку
+type Listener[K comparable] func(event Event[K]) error
+
+// Слушатели и подписчики одного графа. Общие для графа и его Batch
+type observers[K comparable] struct {
+	lock          sync.RWMutex
+	next          int
+	listeners     []listenerEntry[K]
+	subscriptions []*subscription[K]
+
+	// Сколько всего слушателей и подписчиков. Без них изменения идут без событий
+	count atomic.Int32
+}
+
+type listenerEntry[K comparable] struct {
+	id       int
+	listener Listener[K]
+}
+
+func newObservers[K comparable]() *observers[K] {
+	return &observers[K]{}
+}
+
+func (o *observers[K]) active() bool {
+	return o.count.Load() > 0
+}
+
+func (o *observers[K]) observe(listener Listener[K]) func() {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	id := o.next
+	o.next++
+	o.listeners = append(o.listeners, listenerEntry[K]{id: id, listener: listener})
+	o.count.Add(1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			o.lock.Lock()
+			defer o.lock.Unlock()
+
+			for i, entry := range o.listeners {
+				if entry.id == id {
+					o.listeners = append(o.listeners[:i:i], o.listeners[i+1:]...)
+					break
+				}
+			}
+			o.count.Add(-1)
+		})
+	}
+}
+
+func (o *observers[K]) subscribe(buffer int) (<-chan Event[K], func()) {
+	s := &subscription[K]{
+		events: make(chan Event[K], buffer),
+		wake:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+	}
+
+	o.lock.Lock()
+	o.subscriptions = append(o.subscriptions, s)
+	o.lock.Unlock()
+	o.count.Add(1)
+
+	go s.run()
+
+	var once sync.Once
+	return s.events, func() {
+		once.Do(func() {
+			o.lock.Lock()
+			for i, current := range o.subscriptions {
+				if current == s {
+					o.subscriptions = append(o.subscriptions[:i:i], o.subscriptions[i+1:]...)
+					break
+				}
+			}
+			o.lock.Unlock()
+			o.count.Add(-1)
+
+			close(s.stop)
+		})
+	}
+}
+
+// Спрашивает слушателей по порядку регистрации. Первая ошибка запрещает изменение
+func (o *observers[K]) check(event Event[K]) error {
+	// Слушатели вызываются без блокировки, чтобы они могли отписаться
+	o.lock.RLock()
+	listeners := o.listeners
+	o.lock.RUnlock()
+
+	for _, entry := range listeners {
+		if err := entry.listener(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Отдаёт подписчикам события применённых изменений. Не ждёт читателей каналов
+func (o *observers[K]) publish(events []Event[K]) {
+	if len(events) == 0 {
+		return
+	}
+
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+
+	for _, s := range o.subscriptions {
+		s.push(events)
+	}
+}
+
+// Проверяет изменение у слушателей, применяет его и ставит событие в очередь подписчиков.
+// pending это очередь текущего Batch, без него событие уходит подписчикам сразу
+func (o *observers[K]) change(pending *[]Event[K], event Event[K], apply func() error) error {
+	if !o.active() {
+		return apply()
+	}
+
+	if err := o.check(event); err != nil {
+		return err
+	}
+
+	if err := apply(); err != nil {
+		return err
+	}
+
+	if pending != nil {
+		*pending = append(*pending, event)
+	} else {
+		o.publish([]Event[K]{event})
+	}
+
+	return nil
+}
+
+// Асинхронная подписка. События копятся в очереди без ограничения,
+// а отдельная горутина переносит их в канал, поэтому медленный читатель не тормозит запись
+type subscription[K comparable] struct {
+	lock  sync.Mutex
+	queue []Event[K]
+
+	events chan Event[K]
+	wake   chan struct{}
+	stop   chan struct{}
+}
+
+func (s *subscription[K]) push(events []Event[K]) {
+	s.lock.Lock()
+	s.queue = append(s.queue, events...)
+	s.lock.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscription[K]) run() {
+	defer close(s.events)
+
+	for {
+		s.lock.Lock()
+		queue := s.queue
+		s.queue = nil
+		s.lock.Unlock()
+
+		for _, event := range queue {
+			select {
+			case s.events <- event:
+			case <-s.stop:
+				return
+			}
+		}
+
+		select {
+		case <-s.wake:
+		case <-s.stop:
+			return
+		}
+	}
+}
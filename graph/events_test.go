@@ -0,0 +1,301 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Слушатель, который запоминает события и запрещает те, на которые veto вернёт true
+func recordEvents(g Graph[int, int], veto func(Event[int]) bool) (*[]Event[int], func()) {
+	var events []Event[int]
+	forbidden := errors.New("запрещено")
+
+	cancel := g.Observe(func(event Event[int]) error {
+		if veto != nil && veto(event) {
+			return fmt.Errorf("%w: %v", forbidden, event.Kind)
+		}
+		events = append(events, event)
+		return nil
+	})
+
+	return &events, cancel
+}
+
+// Краткая запись события: вид и вершины
+func eventString(event Event[int]) string {
+	switch event.Kind {
+	case VertexAdded:
+		return fmt.Sprintf("+v%d", event.Vertex)
+	case VertexRemoved:
+		return fmt.Sprintf("-v%d", event.Vertex)
+	case VertexEdited:
+		return fmt.Sprintf("~v%d", event.Vertex)
+	case EdgeAdded:
+		return fmt.Sprintf("+e%d%d", event.Source, event.Target)
+	case EdgeEdited:
+		return fmt.Sprintf("~e%d%d", event.Source, event.Target)
+	case EdgeRemoved:
+		return fmt.Sprintf("-e%d%d", event.Source, event.Target)
+	}
+
+	return "?"
+}
+
+func eventStrings(events []Event[int]) []string {
+	res := make([]string, 0, len(events))
+	for _, event := range events {
+		res = append(res, eventString(event))
+	}
+
+	return res
+}
+
+func TestObserveVeto(t *testing.T) {
+	g := directedGraph(t, 3, [][2]int{{1, 2}})
+
+	events, cancel := recordEvents(g, func(event Event[int]) bool {
+		return event.Kind == EdgeAdded && event.Target == 3
+	})
+	defer cancel()
+
+	if err := g.AddEdge(1, 3); err == nil {
+		t.Fatal("запрещённая дуга добавилась")
+	}
+	if _, err := g.Edge(1, 3); !errors.Is(err, ErrorEdgeNotFound) {
+		t.Fatalf("после запрета Edge(1, 3) вернул %v", err)
+	}
+
+	// Запрет посреди Batch откатывает и уже сделанные изменения
+	err := g.Batch(func(tx Graph[int, int]) error {
+		if err := tx.EditEdge(1, 2, EdgeWeight(50)); err != nil {
+			return err
+		}
+		if err := tx.RemoveEdge(1, 2); err != nil {
+			return err
+		}
+		return tx.AddEdge(2, 3)
+	})
+	if err == nil {
+		t.Fatal("Batch с запрещённой дугой прошёл")
+	}
+
+	edge, err := g.Edge(1, 2)
+	if err != nil {
+		t.Fatalf("после отката дуга 1 -> 2 пропала: %v", err)
+	}
+	if edge.Properties.Weight != 12 {
+		t.Fatalf("после отката вес дуги 1 -> 2 равен %d", edge.Properties.Weight)
+	}
+
+	// Слушатель видел события до запрета, но без запрещённого
+	if got, want := fmt.Sprint(eventStrings(*events)), "[~e12 -e12]"; got != want {
+		t.Fatalf("события %s, ожидалось %s", got, want)
+	}
+}
+
+func TestObserveCascadeOrder(t *testing.T) {
+	for _, isDirected := range []bool{true, false} {
+		t.Run(fmt.Sprint("directed=", isDirected), func(t *testing.T) {
+			var options []func(*Traits)
+			if isDirected {
+				options = append(options, Directed())
+			}
+
+			g := New(IntHash, options...)
+			for i := 1; i <= 3; i++ {
+				if err := g.AddVertex(i); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, edge := range [][2]int{{1, 2}, {2, 2}, {2, 3}} {
+				if err := g.AddEdge(edge[0], edge[1]); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			events, cancel := recordEvents(g, nil)
+			defer cancel()
+
+			if _, err := g.RemoveVertexCascade(2); err != nil {
+				t.Fatal(err)
+			}
+
+			// По событию на дугу, петля одна, и вершина последней
+			got := *events
+			if len(got) != 4 || got[3].Kind != VertexRemoved || got[3].Vertex != 2 {
+				t.Fatalf("события %v, ожидалось три дуги и затем вершина 2", eventStrings(got))
+			}
+
+			seen := make(map[[2]int]bool)
+			for i, event := range got[:3] {
+				if event.Kind != EdgeRemoved {
+					t.Fatalf("событие %d: %s вместо удаления дуги", i, eventString(event))
+				}
+
+				ends := [2]int{event.Source, event.Target}
+				if !isDirected && ends[0] > ends[1] {
+					ends = [2]int{ends[1], ends[0]}
+				}
+				seen[ends] = true
+
+				// У направленного графа исходящие дуги идут раньше входящих
+				if isDirected && event.Target == 2 && event.Source != 2 && i != 2 {
+					t.Fatalf("входящая дуга удалена %d-й: %v", i, eventStrings(got))
+				}
+			}
+
+			for _, ends := range [][2]int{{1, 2}, {2, 2}, {2, 3}} {
+				if !seen[ends] {
+					t.Fatalf("нет события для дуги %v: %v", ends, eventStrings(got))
+				}
+			}
+		})
+	}
+}
+
+// Запрет удаления вершины откатывает уже удалённые дуги каскада
+func TestObserveCascadeVeto(t *testing.T) {
+	g := directedGraph(t, 3, [][2]int{{1, 2}, {2, 3}, {2, 2}})
+	want := edgeList(t, g)
+
+	events, cancel := recordEvents(g, func(event Event[int]) bool {
+		return event.Kind == VertexRemoved
+	})
+	defer cancel()
+
+	if _, err := g.RemoveVertexCascade(2); err == nil {
+		t.Fatal("запрещённое удаление вершины прошло")
+	}
+	if len(*events) != 3 {
+		t.Fatalf("события %v, ожидалось три удаления дуг до запрета", eventStrings(*events))
+	}
+
+	if got := edgeList(t, g); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("после отката дуги %v, ожидалось %v", got, want)
+	}
+}
+
+func TestObserveUnsubscribe(t *testing.T) {
+	g := directedGraph(t, 2, nil)
+
+	first, cancelFirst := recordEvents(g, nil)
+	second, cancelSecond := recordEvents(g, nil)
+
+	if err := g.AddEdge(1, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	cancelFirst()
+	// Повторная отписка ничего не ломает
+	cancelFirst()
+
+	if err := g.RemoveEdge(1, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fmt.Sprint(eventStrings(*first)); got != "[+e12]" {
+		t.Fatalf("отписанный слушатель получил %s", got)
+	}
+	if got := fmt.Sprint(eventStrings(*second)); got != "[+e12 -e12]" {
+		t.Fatalf("оставшийся слушатель получил %s", got)
+	}
+
+	cancelSecond()
+	if d := g.(*directed[int, int]); d.events.active() {
+		t.Fatal("после отписки всех слушателей события всё ещё собираются")
+	}
+
+	// Слушатель может отписаться прямо из события
+	var calls int
+	var cancel func()
+	cancel = g.Observe(func(Event[int]) error {
+		calls++
+		cancel()
+		return nil
+	})
+	if err := g.AddVertex(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddVertex(4); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("слушатель вызван %d раз после отписки из события", calls)
+	}
+}
+
+func TestSubscribeBatch(t *testing.T) {
+	g := directedGraph(t, 3, nil)
+
+	events, cancel := g.Subscribe(0)
+
+	failed := errors.New("откат")
+	err := g.Batch(func(tx Graph[int, int]) error {
+		if err := tx.AddEdge(1, 2); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("Batch вернул %v", err)
+	}
+
+	err = g.Batch(func(tx Graph[int, int]) error {
+		if err := tx.AddEdge(2, 3); err != nil {
+			return err
+		}
+		return tx.AddEdge(3, 1)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// События откатившегося Batch подписчик не получает
+	var got []string
+	for len(got) < 2 {
+		select {
+		case event := <-events:
+			got = append(got, eventString(event))
+		case <-time.After(5 * time.Second):
+			t.Fatalf("получены только %v", got)
+		}
+	}
+	if fmt.Sprint(got) != "[+e23 +e31]" {
+		t.Fatalf("подписчик получил %v", got)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+// Цена событий: без слушателей изменения не собирают их вовсе
+func BenchmarkObserveOverhead(b *testing.B) {
+	for _, listeners := range []int{0, 1} {
+		b.Run(fmt.Sprint("listeners=", listeners), func(b *testing.B) {
+			g := New(IntHash, Directed())
+			for i := 0; i < 2; i++ {
+				if err := g.AddVertex(i); err != nil {
+					b.Fatal(err)
+				}
+			}
+			for i := 0; i < listeners; i++ {
+				defer g.Observe(func(Event[int]) error { return nil })()
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if err := g.AddEdge(0, 1); err != nil {
+					b.Fatal(err)
+				}
+				if err := g.RemoveEdge(0, 1); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,763 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Хранилище графа на диске.
+// Данные живут в памяти, а каждое изменение дописывается в журнал (write-ahead log).
+// При открытии читается снимок и поверх него проигрывается журнал.
+// Compact сворачивает журнал в новый снимок.
+//
+// Значения вершин и EdgeProperties.Data кодируются через encoding/gob,
+// поэтому конкретные типы внутри Data нужно зарегистрировать через gob.Register.
+//
+// StoreFactory не реализован: каталог для копии не выбрать за вызывающего,
+// поэтому Clone и NewLike такого графа создают граф в памяти
+type FileStore[K comparable, T any] struct {
+	// Сериализует запись в журнал и применение изменений
+	lock sync.Mutex
+
+	memory  *MemoryStore[K, T]
+	dir     string
+	log     *os.File
+	options FileStoreOptions
+
+	// Номер последней записи журнала
+	seq uint64
+	// Записей в журнале с последнего сжатия
+	records int
+	// Были записи без fsync
+	dirty bool
+	// Первая ошибка записи. После неё хранилище только читается
+	err error
+
+	// Внутри Batch записи копятся здесь и уходят в журнал одной группой
+	batch bool
+	group []walRecord[K, T]
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Когда вызывать fsync для журнала
+type SyncPolicy int
+
+const (
+	// После каждой записи
+	SyncAlways SyncPolicy = iota
+	// Никогда, сброс на диск остаётся операционной системе
+	SyncNever
+	// Фоном раз в FileStoreOptions.SyncInterval
+	SyncPeriodic
+)
+
+type FileStoreOptions struct {
+	Sync         SyncPolicy
+	SyncInterval time.Duration
+	// Сколько записей журнала копить до автоматического сжатия. 0 отключает сжатие
+	CompactAfter int
+}
+
+// fsync после каждой записи. Поведение по умолчанию
+func SyncEachWrite() func(*FileStoreOptions) {
+	return func(o *FileStoreOptions) {
+		o.Sync = SyncAlways
+	}
+}
+
+// Без fsync
+func SyncNone() func(*FileStoreOptions) {
+	return func(o *FileStoreOptions) {
+		o.Sync = SyncNever
+	}
+}
+
+// fsync раз в заданный интервал
+func SyncEvery(interval time.Duration) func(*FileStoreOptions) {
+	return func(o *FileStoreOptions) {
+		o.Sync = SyncPeriodic
+		o.SyncInterval = interval
+	}
+}
+
+// Автоматическое сжатие журнала после заданного числа записей
+func CompactAfter(records int) func(*FileStoreOptions) {
+	return func(o *FileStoreOptions) {
+		o.CompactAfter = records
+	}
+}
+
+const (
+	fileStoreLog      = "graph.wal"
+	fileStoreSnapshot = "graph.snapshot"
+
+	// Длина и контрольная сумма перед каждой записью журнала
+	walHeaderSize = 8
+)
+
+// Виды записей журнала
+const (
+	walAddVertex uint8 = iota + 1
+	walRemoveVertex
+	walAddEdge
+	walEditEdge
+	walRemoveEdge
+	walEditVertex
+	walAddParallelEdge
+	walEditParallelEdge
+	walRemoveParallelEdge
+	// Все изменения одного Batch. Применяется целиком или не применяется вовсе
+	walBatch
+)
+
+type walRecord[K comparable, T any] struct {
+	Seq        uint64
+	Op         uint8
+	Source     K
+	Target     K
+	Value      T
+	Properties VertexProperties
+	Edge       Edge[K]
+	// Изменения группы walBatch по порядку
+	Group []walRecord[K, T]
+}
+
+type fileSnapshot[K comparable, T any] struct {
+	Seq      uint64
+	Vertices []snapshotVertex[K, T]
+	Edges    []Edge[K]
+}
+
+type snapshotVertex[K comparable, T any] struct {
+	Hash       K
+	Value      T
+	Properties VertexProperties
+}
+
+// Открывает хранилище в каталоге dir, создавая его при необходимости.
+// Оборванная последняя запись журнала после сбоя отбрасывается
+func OpenFileStore[K comparable, T any](dir string, options ...func(*FileStoreOptions)) (*FileStore[K, T], error) {
+	opts := FileStoreOptions{
+		Sync:         SyncAlways,
+		SyncInterval: time.Second,
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &FileStore[K, T]{
+		memory:  NewMemoryStore[K, T](),
+		dir:     dir,
+		options: opts,
+	}
+
+	if err := s.loadSnapshot(); err != nil {
+		return nil, err
+	}
+
+	log, err := os.OpenFile(filepath.Join(dir, fileStoreLog), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	s.log = log
+
+	if err := s.replay(); err != nil {
+		_ = log.Close()
+		return nil, err
+	}
+
+	if opts.Sync == SyncPeriodic && opts.SyncInterval > 0 {
+		s.stop = make(chan struct{})
+		s.done = make(chan struct{})
+		go s.syncLoop()
+	}
+
+	return s, nil
+}
+
+func (s *FileStore[K, T]) loadSnapshot() error {
+	file, err := os.Open(filepath.Join(s.dir, fileStoreSnapshot))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var snapshot fileSnapshot[K, T]
+	if err := gob.NewDecoder(file).Decode(&snapshot); err != nil {
+		return err
+	}
+
+	for _, vertex := range snapshot.Vertices {
+		if err := s.memory.AddVertex(vertex.Hash, vertex.Value, withAttributes(vertex.Properties)); err != nil {
+			return err
+		}
+	}
+
+	for _, edge := range snapshot.Edges {
+		add := s.memory.AddEdge
+		// Дуги мультиграфа с одной парой вершин не должны затирать друг друга
+		if edge.Properties.ID != 0 {
+			add = s.memory.AddParallelEdge
+		}
+
+		if err := add(edge.Source, edge.Target, withEdgeAttributes(edge)); err != nil {
+			return err
+		}
+	}
+
+	s.seq = snapshot.Seq
+
+	return nil
+}
+
+// Проигрывает журнал поверх снимка. Всё после первой битой записи обрезается
+func (s *FileStore[K, T]) replay() error {
+	info, err := s.log.Stat()
+	if err != nil {
+		return err
+	}
+
+	var offset int64
+	header := make([]byte, walHeaderSize)
+
+	for {
+		if _, err := io.ReadFull(s.log, header); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return err
+		}
+
+		size := binary.LittleEndian.Uint32(header[0:4])
+		sum := binary.LittleEndian.Uint32(header[4:8])
+
+		// Размер из битого заголовка может быть любым. Запись длиннее остатка
+		// файла всё равно оборвана, память под неё не выделяем
+		if int64(size) > info.Size()-offset-walHeaderSize {
+			break
+		}
+
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(s.log, payload); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return err
+		}
+
+		if crc32.ChecksumIEEE(payload) != sum {
+			break
+		}
+
+		var record walRecord[K, T]
+		if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&record); err != nil {
+			break
+		}
+
+		// Записи, уже попавшие в снимок, пропускаем
+		if record.Seq > s.seq {
+			if err := s.apply(record); err != nil {
+				return err
+			}
+			s.seq = record.Seq
+		}
+
+		offset += walHeaderSize + int64(size)
+		s.records++
+	}
+
+	// Отрезаем оборванный хвост и дописываем дальше с его места
+	if err := s.log.Truncate(offset); err != nil {
+		return err
+	}
+
+	_, err = s.log.Seek(offset, io.SeekStart)
+	return err
+}
+
+func (s *FileStore[K, T]) apply(record walRecord[K, T]) error {
+	// gob не сохраняет пустые карты, возвращаем их, чтобы опции могли писать в Attributes
+	record.Properties = withAttributes(record.Properties)
+	record.Edge = withEdgeAttributes(record.Edge)
+
+	switch record.Op {
+	case walBatch:
+		for _, change := range record.Group {
+			if err := s.apply(change); err != nil {
+				return err
+			}
+		}
+		return nil
+	case walAddVertex:
+		return s.memory.AddVertex(record.Source, record.Value, record.Properties)
+	case walRemoveVertex:
+		return s.memory.RemoveVertex(record.Source)
+	case walEditVertex:
+		return s.memory.EditVertex(record.Source, record.Properties)
+	case walAddEdge:
+		return s.memory.AddEdge(record.Source, record.Target, record.Edge)
+	case walEditEdge:
+		return s.memory.EditEdge(record.Source, record.Target, record.Edge)
+	case walRemoveEdge:
+		return s.memory.RemoveEdge(record.Source, record.Target)
+	case walAddParallelEdge:
+		return s.memory.AddParallelEdge(record.Source, record.Target, record.Edge)
+	case walEditParallelEdge:
+		return s.memory.EditParallelEdge(record.Source, record.Target, record.Edge)
+	case walRemoveParallelEdge:
+		return s.memory.RemoveParallelEdge(record.Source, record.Target, record.Edge.Properties.ID)
+	}
+
+	return nil
+}
+
+// Применяет изменение в памяти и дописывает его в журнал.
+// Вызывается под s.lock
+func (s *FileStore[K, T]) write(record walRecord[K, T]) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if err := s.apply(record); err != nil {
+		return err
+	}
+
+	s.seq++
+	record.Seq = s.seq
+
+	if s.batch {
+		s.group = append(s.group, record)
+		return nil
+	}
+
+	if err := s.append(record); err != nil {
+		// Память и диск разошлись, дальше писать нельзя
+		s.err = err
+		return err
+	}
+
+	if s.options.CompactAfter > 0 && s.records >= s.options.CompactAfter {
+		return s.compact()
+	}
+
+	return nil
+}
+
+func (s *FileStore[K, T]) append(record walRecord[K, T]) error {
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(record); err != nil {
+		return err
+	}
+
+	frame := make([]byte, walHeaderSize, walHeaderSize+payload.Len())
+	binary.LittleEndian.PutUint32(frame[0:4], uint32(payload.Len()))
+	binary.LittleEndian.PutUint32(frame[4:8], crc32.ChecksumIEEE(payload.Bytes()))
+	frame = append(frame, payload.Bytes()...)
+
+	if _, err := s.log.Write(frame); err != nil {
+		return err
+	}
+
+	s.records++
+	s.dirty = true
+
+	if s.options.Sync == SyncAlways {
+		return s.sync()
+	}
+
+	return nil
+}
+
+func (s *FileStore[K, T]) sync() error {
+	if !s.dirty {
+		return nil
+	}
+
+	if err := s.log.Sync(); err != nil {
+		return err
+	}
+
+	s.dirty = false
+
+	return nil
+}
+
+func (s *FileStore[K, T]) syncLoop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.options.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.lock.Lock()
+			if err := s.sync(); err != nil && s.err == nil {
+				s.err = err
+			}
+			s.lock.Unlock()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Сбрасывает журнал на диск
+func (s *FileStore[K, T]) Sync() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.sync()
+}
+
+// Записывает снимок текущего состояния и очищает журнал
+func (s *FileStore[K, T]) Compact() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	return s.compact()
+}
+
+func (s *FileStore[K, T]) compact() error {
+	snapshot := fileSnapshot[K, T]{Seq: s.seq}
+
+	hashes, err := s.memory.ListVertices()
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range hashes {
+		value, properties, err := s.memory.Vertex(hash)
+		if err != nil {
+			return err
+		}
+		snapshot.Vertices = append(snapshot.Vertices, snapshotVertex[K, T]{
+			Hash:       hash,
+			Value:      value,
+			Properties: properties,
+		})
+	}
+
+	if snapshot.Edges, err = s.memory.ListEdges(); err != nil {
+		return err
+	}
+
+	// Пишем во временный файл и подменяем, чтобы снимок не остался наполовину записанным
+	path := filepath.Join(s.dir, fileStoreSnapshot)
+	tmp, err := os.CreateTemp(s.dir, fileStoreSnapshot+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(snapshot); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	if err := syncDir(s.dir); err != nil {
+		return err
+	}
+
+	// Если упадём здесь, записи журнала уже есть в снимке и будут пропущены по Seq
+	if err := s.log.Truncate(0); err != nil {
+		s.err = err
+		return err
+	}
+
+	if _, err := s.log.Seek(0, io.SeekStart); err != nil {
+		s.err = err
+		return err
+	}
+
+	s.records = 0
+	s.dirty = true
+
+	return s.sync()
+}
+
+func withAttributes(properties VertexProperties) VertexProperties {
+	if properties.Attributes == nil {
+		properties.Attributes = make(map[string]string)
+	}
+
+	return properties
+}
+
+func withEdgeAttributes[K comparable](edge Edge[K]) Edge[K] {
+	if edge.Properties.Attributes == nil {
+		edge.Properties.Attributes = make(map[string]string)
+	}
+
+	return edge
+}
+
+func syncDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return file.Sync()
+}
+
+// Сбрасывает журнал и закрывает файлы
+func (s *FileStore[K, T]) Close() error {
+	if s.stop != nil {
+		close(s.stop)
+		<-s.done
+		s.stop = nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	syncErr := s.sync()
+	closeErr := s.log.Close()
+
+	if syncErr != nil {
+		return syncErr
+	}
+
+	return closeErr
+}
+
+func (s *FileStore[K, T]) View(fn func(view Store[K, T]) error) error {
+	return s.memory.View(fn)
+}
+
+// Снимок данных в памяти, журнал он не трогает
+func (s *FileStore[K, T]) Snapshot() (Store[K, T], func()) {
+	return s.memory.Snapshot()
+}
+
+// Блокирует хранилище на всё время fn. Изменения и их откат уходят в журнал
+// одной записью после fn, так что сбой посреди группы не оставит её наполовину применённой
+func (s *FileStore[K, T]) Batch(fn func(tx Store[K, T]) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// Держим и блокировку памяти, чтобы читатели не видели незавершённую группу
+	s.memory.lock.Lock()
+	defer s.memory.lock.Unlock()
+
+	memory := s.memory.view()
+	view := &FileStore[K, T]{
+		memory:  memory,
+		dir:     s.dir,
+		log:     s.log,
+		options: s.options,
+		seq:     s.seq,
+		records: s.records,
+		dirty:   s.dirty,
+		err:     s.err,
+		batch:   true,
+	}
+
+	err := runTx[K, T](view, fn)
+	if commitErr := view.commitGroup(); commitErr != nil {
+		err = errors.Join(err, commitErr)
+	}
+
+	s.memory.adopt(memory)
+	s.seq = view.seq
+	s.records = view.records
+	s.dirty = view.dirty
+	s.err = view.err
+
+	return err
+}
+
+// Дописывает накопленную в Batch группу одной записью журнала
+func (s *FileStore[K, T]) commitGroup() error {
+	if len(s.group) == 0 || s.err != nil {
+		return nil
+	}
+
+	if err := s.append(walRecord[K, T]{Seq: s.seq, Op: walBatch, Group: s.group}); err != nil {
+		// Память и диск разошлись, дальше писать нельзя
+		s.err = err
+		return err
+	}
+	s.group = nil
+
+	if s.options.CompactAfter > 0 && s.records >= s.options.CompactAfter {
+		return s.compact()
+	}
+
+	return nil
+}
+
+func (s *FileStore[K, T]) AddVertex(hash K, value T, properties VertexProperties) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.write(walRecord[K, T]{Op: walAddVertex, Source: hash, Value: value, Properties: properties})
+}
+
+func (s *FileStore[K, T]) RemoveVertex(hash K) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.write(walRecord[K, T]{Op: walRemoveVertex, Source: hash})
+}
+
+func (s *FileStore[K, T]) AddEdge(source, target K, edge Edge[K]) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.write(walRecord[K, T]{Op: walAddEdge, Source: source, Target: target, Edge: edge})
+}
+
+func (s *FileStore[K, T]) EditVertex(hash K, properties VertexProperties) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.write(walRecord[K, T]{Op: walEditVertex, Source: hash, Properties: properties})
+}
+
+func (s *FileStore[K, T]) EditEdge(source, target K, edge Edge[K]) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.write(walRecord[K, T]{Op: walEditEdge, Source: source, Target: target, Edge: edge})
+}
+
+func (s *FileStore[K, T]) RemoveEdge(source, target K) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.write(walRecord[K, T]{Op: walRemoveEdge, Source: source, Target: target})
+}
+
+func (s *FileStore[K, T]) AddParallelEdge(source, target K, edge Edge[K]) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.write(walRecord[K, T]{Op: walAddParallelEdge, Source: source, Target: target, Edge: edge})
+}
+
+func (s *FileStore[K, T]) EditParallelEdge(source, target K, edge Edge[K]) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.write(walRecord[K, T]{Op: walEditParallelEdge, Source: source, Target: target, Edge: edge})
+}
+
+func (s *FileStore[K, T]) RemoveParallelEdge(source, target K, id uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	edge := Edge[K]{Properties: EdgeProperties{ID: id}}
+
+	return s.write(walRecord[K, T]{Op: walRemoveParallelEdge, Source: source, Target: target, Edge: edge})
+}
+
+// Чтение идёт из памяти
+
+func (s *FileStore[K, T]) Vertex(hash K) (T, VertexProperties, error) {
+	return s.memory.Vertex(hash)
+}
+
+func (s *FileStore[K, T]) ListVertices() ([]K, error) {
+	return s.memory.ListVertices()
+}
+
+func (s *FileStore[K, T]) VertexCount() (int, error) {
+	return s.memory.VertexCount()
+}
+
+func (s *FileStore[K, T]) Edge(source, target K) (Edge[K], error) {
+	return s.memory.Edge(source, target)
+}
+
+func (s *FileStore[K, T]) ListEdges() ([]Edge[K], error) {
+	return s.memory.ListEdges()
+}
+
+func (s *FileStore[K, T]) InDegree(hash K) (int, error) {
+	return s.memory.InDegree(hash)
+}
+
+func (s *FileStore[K, T]) OutDegree(hash K) (int, error) {
+	return s.memory.OutDegree(hash)
+}
+
+func (s *FileStore[K, T]) Neighbors(hash K) ([]K, error) {
+	return s.memory.Neighbors(hash)
+}
+
+func (s *FileStore[K, T]) Predecessors(hash K) ([]K, error) {
+	return s.memory.Predecessors(hash)
+}
+
+func (s *FileStore[K, T]) OutEdges(hash K) ([]Edge[K], error) {
+	return s.memory.OutEdges(hash)
+}
+
+func (s *FileStore[K, T]) InEdges(hash K) ([]Edge[K], error) {
+	return s.memory.InEdges(hash)
+}
+
+func (s *FileStore[K, T]) EdgesBetween(source, target K) ([]Edge[K], error) {
+	return s.memory.EdgesBetween(source, target)
+}
+
+func (s *FileStore[K, T]) EdgeByID(id uint64) (Edge[K], error) {
+	return s.memory.EdgeByID(id)
+}
+
+// Индексы живут только в памяти и в журнал не пишутся,
+// поэтому после открытия хранилища их нужно объявить заново
+
+func (s *FileStore[K, T]) CreateIndex(attribute string) error {
+	return s.memory.CreateIndex(attribute)
+}
+
+func (s *FileStore[K, T]) CreateWeightIndex() error {
+	return s.memory.CreateWeightIndex()
+}
+
+func (s *FileStore[K, T]) VerticesByAttribute(attribute, value string) ([]K, error) {
+	return s.memory.VerticesByAttribute(attribute, value)
+}
+
+func (s *FileStore[K, T]) VerticesByWeight(min, max float64) ([]K, error) {
+	return s.memory.VerticesByWeight(min, max)
+}
@@ -0,0 +1,343 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Состояние хранилища строкой: вершины со свойствами и дуги, всё по порядку
+func storeState[K comparable, T any](t *testing.T, s Store[K, T]) string {
+	t.Helper()
+
+	hashes, err := s.ListVertices()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := make([]string, 0)
+	for _, hash := range hashes {
+		value, properties, err := s.Vertex(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, fmt.Sprintf("v %v %v %v", hash, value, properties))
+	}
+
+	edges, err := s.ListEdges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, edge := range edges {
+		lines = append(lines, fmt.Sprintf("e %v %v %v", edge.Source, edge.Target, edge.Properties))
+	}
+
+	slices.Sort(lines)
+
+	return strings.Join(lines, "\n")
+}
+
+func openFileStore(t *testing.T, dir string, options ...func(*FileStoreOptions)) *FileStore[int, int] {
+	t.Helper()
+
+	s, err := OpenFileStore[int, int](dir, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func vertexAttribute(key, value string) func(*VertexProperties) {
+	return func(p *VertexProperties) {
+		p.Attributes[key] = value
+	}
+}
+
+// Набор изменений всех видов, включая откатившийся Batch
+func fillFileStore(t *testing.T, s *FileStore[int, int], from int) {
+	t.Helper()
+
+	g := NewWithStore(IntHash, Store[int, int](s), Directed(), FloatWeighted())
+
+	for i := from; i < from+10; i++ {
+		if err := g.AddVertex(i, VertexWeight(i), vertexAttribute("name", fmt.Sprint("v", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := from; i < from+9; i++ {
+		if err := g.AddEdge(i, i+1, EdgeWeightOf(float64(i)/4), EdgeType("NEXT")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := g.EditEdge(from, from+1, EdgeWeight(100)); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RemoveEdge(from+8, from+9); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RemoveVertex(from + 9); err != nil {
+		t.Fatal(err)
+	}
+
+	failed := errors.New("откат")
+	err := g.Batch(func(tx Graph[int, int]) error {
+		if err := tx.AddVertex(from + 100); err != nil {
+			return err
+		}
+		if err := tx.RemoveEdge(from+1, from+2); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("Batch вернул %v", err)
+	}
+}
+
+func TestFileStoreReplayMatchesMemory(t *testing.T) {
+	dir := t.TempDir()
+
+	s := openFileStore(t, dir, SyncNone())
+	fillFileStore(t, s, 0)
+	want := storeState[int, int](t, s)
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := openFileStore(t, dir)
+	defer reopened.Close()
+
+	if got := storeState[int, int](t, reopened); got != want {
+		t.Fatalf("после перезапуска:\n%s\nв памяти было:\n%s", got, want)
+	}
+}
+
+func TestFileStoreCompactAndReopen(t *testing.T) {
+	dir := t.TempDir()
+
+	s := openFileStore(t, dir)
+	fillFileStore(t, s, 0)
+
+	if err := s.Compact(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Часть изменений в снимке, часть в журнале после него
+	fillFileStore(t, s, 1000)
+	want := storeState[int, int](t, s)
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := openFileStore(t, dir)
+	if got := storeState[int, int](t, reopened); got != want {
+		t.Fatalf("после сжатия и перезапуска:\n%s\nв памяти было:\n%s", got, want)
+	}
+
+	// Повторное сжатие уже проигранного журнала ничего не теряет
+	if err := reopened.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	if err := reopened.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	again := openFileStore(t, dir)
+	defer again.Close()
+
+	if got := storeState[int, int](t, again); got != want {
+		t.Fatalf("после второго сжатия:\n%s\nв памяти было:\n%s", got, want)
+	}
+}
+
+func TestFileStoreAutoCompact(t *testing.T) {
+	dir := t.TempDir()
+
+	s := openFileStore(t, dir, CompactAfter(7))
+	fillFileStore(t, s, 0)
+	want := storeState[int, int](t, s)
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := openFileStore(t, dir)
+	defer reopened.Close()
+
+	if got := storeState[int, int](t, reopened); got != want {
+		t.Fatalf("после автоматического сжатия:\n%s\nв памяти было:\n%s", got, want)
+	}
+}
+
+func TestFileStoreTruncatedTail(t *testing.T) {
+	base := t.TempDir()
+	source := filepath.Join(base, "source")
+
+	s := openFileStore(t, source)
+	fillFileStore(t, s, 0)
+	want := storeState[int, int](t, s)
+
+	info, err := os.Stat(filepath.Join(source, fileStoreLog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	complete := info.Size()
+
+	if err := s.AddVertex(500, 500, VertexProperties{Attributes: map[string]string{"name": "tail"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	log, err := os.ReadFile(filepath.Join(source, fileStoreLog))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Обрыв в заголовке, внутри данных и за байт до конца последней записи
+	cuts := []int64{complete + 1, complete + walHeaderSize, complete + walHeaderSize + 3, int64(len(log)) - 1}
+
+	for _, cut := range cuts {
+		t.Run(fmt.Sprint(cut), func(t *testing.T) {
+			dir := filepath.Join(base, fmt.Sprint("cut", cut))
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, fileStoreLog), log[:cut], 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			reopened := openFileStore(t, dir)
+			if got := storeState[int, int](t, reopened); got != want {
+				t.Fatalf("после обрыва:\n%s\nожидалось:\n%s", got, want)
+			}
+
+			// Хвост отрезан, новая запись ложится сразу за последней целой
+			if err := reopened.AddVertex(600, 600, VertexProperties{}); err != nil {
+				t.Fatal(err)
+			}
+			after := storeState[int, int](t, reopened)
+
+			if err := reopened.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			again := openFileStore(t, dir)
+			defer again.Close()
+
+			if got := storeState[int, int](t, again); got != after {
+				t.Fatalf("запись после обрыва потерялась:\n%s\nожидалось:\n%s", got, after)
+			}
+		})
+	}
+}
+
+func TestFileStoreTornBatch(t *testing.T) {
+	base := t.TempDir()
+	source := filepath.Join(base, "source")
+
+	s := openFileStore(t, source)
+	fillFileStore(t, s, 0)
+	want := storeState[int, int](t, s)
+
+	info, err := os.Stat(filepath.Join(source, fileStoreLog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	complete := info.Size()
+
+	g := NewWithStore(IntHash, Store[int, int](s), Directed())
+	err = g.Batch(func(tx Graph[int, int]) error {
+		for i := 500; i < 510; i++ {
+			if err := tx.AddVertex(i); err != nil {
+				return err
+			}
+		}
+		return tx.AddEdge(500, 501)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	log, err := os.ReadFile(filepath.Join(source, fileStoreLog))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Вся группа лежит одной записью, любой обрыв внутри неё отбрасывает её целиком
+	for _, cut := range []int64{complete + walHeaderSize + 1, (complete + int64(len(log))) / 2, int64(len(log)) - 1} {
+		dir := filepath.Join(base, fmt.Sprint("cut", cut))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, fileStoreLog), log[:cut], 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		reopened := openFileStore(t, dir)
+		if got := storeState[int, int](t, reopened); got != want {
+			t.Fatalf("обрыв на %d оставил часть группы:\n%s\nожидалось:\n%s", cut, got, want)
+		}
+		if err := reopened.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFileStoreOversizedRecord(t *testing.T) {
+	dir := t.TempDir()
+
+	s := openFileStore(t, dir)
+	fillFileStore(t, s, 0)
+	want := storeState[int, int](t, s)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, fileStoreLog)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	complete := info.Size()
+
+	// Заголовок обещает почти 4 ГиБ данных, а за ним несколько байт
+	log, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := log.Write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0, 1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := openFileStore(t, dir)
+	defer reopened.Close()
+
+	if got := storeState[int, int](t, reopened); got != want {
+		t.Fatalf("после битого заголовка:\n%s\nожидалось:\n%s", got, want)
+	}
+
+	info, err = os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != complete {
+		t.Fatalf("журнал %d байт, хвост должен обрезаться до %d", info.Size(), complete)
+	}
+}
@@ -0,0 +1,554 @@
+package graph
+
+import (
+	"sort"
+	"sync"
+)
+
+// Неизменяемый снимок графа в формате CSR (compressed sparse row).
+// Вершины перенумерованы в 0..n-1, дуги каждой вершины лежат подряд в общих срезах,
+// а свойства дуг хранятся в параллельных срезах.
+// Реализует Graph, поэтому на нём работают все алгоритмы пакета.
+// Все изменяющие методы возвращают ErrorGraphReadOnly
+type Frozen[K comparable, T any] struct {
+	hash   Hash[K, T]
+	traits *Traits
+
+	keys       []K
+	index      map[K]int
+	values     []T
+	properties []VertexProperties
+
+	// Дуги вершины i: outTargets[outOffsets[i]:outOffsets[i+1]]
+	outOffsets []int
+	outTargets []int
+	outWeights []int
+	// Вес для алгоритмов: FloatWeight у графа FloatWeighted, иначе Weight
+	outFloatWeights []float64
+	outAttributes   []map[string]string
+	outData         []any
+	outIDs          []uint64
+	outTypes        []string
+
+	// Номер исходящей дуги мультиграфа по её ID
+	ids map[uint64]int
+
+	// Петли вершины i и всего графа. Петля ненаправленного графа хранится одной половиной
+	loops     []int
+	loopCount int
+
+	// Входящие дуги хранят номер исходящей дуги, чтобы не копировать свойства
+	inOffsets []int
+	inSources []int
+	inEdges   []int
+}
+
+// Строит снимок графа. Дальнейшие изменения g на снимок не влияют.
+// Граф читается целиком под одной блокировкой хранилища, так что
+// одновременная запись не разорвёт снимок
+func Freeze[K comparable, T any](g Graph[K, T]) (*Frozen[K, T], error) {
+	var f *Frozen[K, T]
+
+	err := readGraph(g, func(view Graph[K, T]) error {
+		var err error
+		f, err = freeze(view)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func freeze[K comparable, T any](g Graph[K, T]) (*Frozen[K, T], error) {
+	adjacencyMap, err := g.AdjacencyMap()
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(adjacencyMap)
+	f := &Frozen[K, T]{
+		traits: &Traits{
+			IsDirected:      g.Traits().IsDirected,
+			IsWeighted:      g.Traits().IsWeighted,
+			IsFloatWeighted: g.Traits().IsFloatWeighted,
+			IsRooted:        g.Traits().IsRooted,
+			IsTree:          g.Traits().IsTree,
+			IsMultigraph:    g.Traits().IsMultigraph,
+			root:            currentRoot(g),
+		},
+		keys:       make([]K, 0, n),
+		index:      make(map[K]int, n),
+		values:     make([]T, 0, n),
+		properties: make([]VertexProperties, 0, n),
+		outOffsets: make([]int, 1, n+1),
+		inOffsets:  make([]int, n+1),
+		loops:      make([]int, n),
+	}
+
+	if inner, ok := g.(storeGraph[K, T]); ok {
+		f.hash = inner.hashFunc()
+	}
+
+	for hash := range adjacencyMap {
+		value, properties, err := g.VertexWithProperties(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		f.index[hash] = len(f.keys)
+		f.keys = append(f.keys, hash)
+		f.values = append(f.values, value)
+		f.properties = append(f.properties, properties)
+	}
+
+	if f.traits.IsMultigraph {
+		f.ids = make(map[uint64]int)
+	}
+
+	for _, hash := range f.keys {
+		for target, edge := range adjacencyMap[hash] {
+			// В карте смежности одна дуга пары, параллельные берём отдельно
+			edges := []Edge[K]{edge}
+			if f.traits.IsMultigraph {
+				if edges, err = g.EdgesBetween(hash, target); err != nil {
+					return nil, err
+				}
+			}
+
+			j := f.index[target]
+			for _, edge := range edges {
+				// У ненаправленной дуги две половины, запоминаем первую
+				if _, ok := f.ids[edge.Properties.ID]; f.traits.IsMultigraph && !ok {
+					f.ids[edge.Properties.ID] = len(f.outTargets)
+				}
+
+				f.outTargets = append(f.outTargets, j)
+				f.outWeights = append(f.outWeights, edge.Properties.Weight)
+				f.outFloatWeights = append(f.outFloatWeights, f.weight(edge.Properties))
+				f.outAttributes = append(f.outAttributes, edge.Properties.Attributes)
+				f.outData = append(f.outData, edge.Properties.Data)
+				f.outIDs = append(f.outIDs, edge.Properties.ID)
+				f.outTypes = append(f.outTypes, edge.Properties.Type)
+				f.inOffsets[j+1]++
+
+				if j == f.index[hash] {
+					f.loops[j]++
+					f.loopCount++
+				}
+			}
+		}
+		f.outOffsets = append(f.outOffsets, len(f.outTargets))
+	}
+
+	// Входящие дуги раскладываем подсчётом
+	for i := 0; i < n; i++ {
+		f.inOffsets[i+1] += f.inOffsets[i]
+	}
+
+	f.inSources = make([]int, len(f.outTargets))
+	f.inEdges = make([]int, len(f.outTargets))
+	next := make([]int, n)
+	copy(next, f.inOffsets[:n])
+
+	for source := 0; source < n; source++ {
+		for e := f.outOffsets[source]; e < f.outOffsets[source+1]; e++ {
+			target := f.outTargets[e]
+			f.inSources[next[target]] = source
+			f.inEdges[next[target]] = e
+			next[target]++
+		}
+	}
+
+	return f, nil
+}
+
+// Номер вершины в снимке
+func (f *Frozen[K, T]) VertexIndex(hash K) (int, bool) {
+	i, ok := f.index[hash]
+	return i, ok
+}
+
+// Ключ вершины по её номеру
+func (f *Frozen[K, T]) VertexKey(i int) K {
+	return f.keys[i]
+}
+
+// Номера соседей вершины i. Срез общий для всего снимка, менять его нельзя
+func (f *Frozen[K, T]) NeighborIndexes(i int) []int {
+	return f.outTargets[f.outOffsets[i]:f.outOffsets[i+1]]
+}
+
+// Веса дуг вершины i в том же порядке, что и NeighborIndexes.
+// У графа FloatWeighted это FloatWeight, у остальных Weight
+func (f *Frozen[K, T]) NeighborWeights(i int) []float64 {
+	return f.outFloatWeights[f.outOffsets[i]:f.outOffsets[i+1]]
+}
+
+func (f *Frozen[K, T]) weight(properties EdgeProperties) float64 {
+	if f.traits.IsFloatWeighted {
+		return properties.FloatWeight
+	}
+
+	return float64(properties.Weight)
+}
+
+func (f *Frozen[K, T]) Traits() *Traits {
+	return f.traits
+}
+
+func (f *Frozen[K, T]) AddVertex(_ T, _ ...func(*VertexProperties)) error {
+	return ErrorGraphReadOnly
+}
+
+func (f *Frozen[K, T]) Vertex(hash K) (T, error) {
+	value, _, err := f.VertexWithProperties(hash)
+	return value, err
+}
+
+func (f *Frozen[K, T]) VertexWithProperties(hash K) (T, VertexProperties, error) {
+	i, ok := f.index[hash]
+	if !ok {
+		var empty T
+		return empty, VertexProperties{}, ErrorVertextNotFound
+	}
+
+	return f.values[i], f.properties[i], nil
+}
+
+func (f *Frozen[K, T]) RemoveVertex(_ K) error {
+	return ErrorGraphReadOnly
+}
+
+func (f *Frozen[K, T]) RemoveVertexCascade(_ K) ([]Edge[K], error) {
+	return nil, ErrorGraphReadOnly
+}
+
+func (f *Frozen[K, T]) EditVertex(_ K, _ ...func(*VertexProperties)) error {
+	return ErrorGraphReadOnly
+}
+
+func (f *Frozen[K, T]) AddEdge(_, _ K, _ ...func(*EdgeProperties)) error {
+	return ErrorGraphReadOnly
+}
+
+func (f *Frozen[K, T]) AddEdgeWithID(_, _ K, _ ...func(*EdgeProperties)) (uint64, error) {
+	return 0, ErrorGraphReadOnly
+}
+
+func (f *Frozen[K, T]) AddVerticesFrom(_ Graph[K, T]) error {
+	return ErrorGraphReadOnly
+}
+
+func (f *Frozen[K, T]) AddEdgesFrom(_ Graph[K, T]) error {
+	return ErrorGraphReadOnly
+}
+
+func (f *Frozen[K, T]) EditEdge(_, _ K, _ ...func(properties *EdgeProperties)) error {
+	return ErrorGraphReadOnly
+}
+
+func (f *Frozen[K, T]) RemoveEdge(_, _ K) error {
+	return ErrorGraphReadOnly
+}
+
+func (f *Frozen[K, T]) EditEdgeByID(_ uint64, _ ...func(properties *EdgeProperties)) error {
+	return ErrorGraphReadOnly
+}
+
+func (f *Frozen[K, T]) RemoveEdgeByID(_ uint64) error {
+	return ErrorGraphReadOnly
+}
+
+func (f *Frozen[K, T]) EdgesBetween(source, target K) ([]Edge[K], error) {
+	res := make([]Edge[K], 0)
+
+	i, ok := f.index[source]
+	if !ok {
+		return res, nil
+	}
+
+	j, ok := f.index[target]
+	if !ok {
+		return res, nil
+	}
+
+	for e := f.outOffsets[i]; e < f.outOffsets[i+1]; e++ {
+		if f.outTargets[e] == j {
+			res = append(res, f.edge(i, e))
+		}
+	}
+
+	return res, nil
+}
+
+func (f *Frozen[K, T]) EdgeByID(id uint64) (Edge[K], error) {
+	if !f.traits.IsMultigraph {
+		return Edge[K]{}, ErrorNotMultigraph
+	}
+
+	e, ok := f.ids[id]
+	if !ok {
+		return Edge[K]{}, ErrorEdgeNotFound
+	}
+
+	// Вершина дуги e: первая, чьи дуги кончаются после e
+	source := sort.Search(len(f.keys), func(i int) bool {
+		return f.outOffsets[i+1] > e
+	})
+
+	return f.edge(source, e), nil
+}
+
+func (f *Frozen[K, T]) Batch(_ func(tx Graph[K, T]) error) error {
+	return ErrorGraphReadOnly
+}
+
+// Снимок и так неизменяемый, отпускать нечего
+func (f *Frozen[K, T]) Snapshot() (*Snapshot[K, T], error) {
+	return newSnapshot[K, T](f, func() {}), nil
+}
+
+// Снимок не меняется, поэтому слушатели никогда не вызываются
+func (f *Frozen[K, T]) Observe(_ Listener[K]) func() {
+	return func() {}
+}
+
+// Снимок не меняется, канал закроется только через cancel
+func (f *Frozen[K, T]) Subscribe(buffer int) (<-chan Event[K], func()) {
+	events := make(chan Event[K], buffer)
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			close(events)
+		})
+	}
+}
+
+// Номер дуги source -> target или -1
+func (f *Frozen[K, T]) find(source, target int) int {
+	for e := f.outOffsets[source]; e < f.outOffsets[source+1]; e++ {
+		if f.outTargets[e] == target {
+			return e
+		}
+	}
+
+	return -1
+}
+
+func (f *Frozen[K, T]) edge(source, e int) Edge[K] {
+	return Edge[K]{
+		Source: f.keys[source],
+		Target: f.keys[f.outTargets[e]],
+		Properties: EdgeProperties{
+			Weight:      f.outWeights[e],
+			FloatWeight: f.outFloatWeights[e],
+			Attributes:  f.outAttributes[e],
+			Data:        f.outData[e],
+			ID:          f.outIDs[e],
+			Type:        f.outTypes[e],
+		},
+	}
+}
+
+func (f *Frozen[K, T]) Edge(source, target K) (Edge[T], error) {
+	i, ok := f.index[source]
+	if !ok {
+		return Edge[T]{}, ErrorEdgeNotFound
+	}
+
+	j, ok := f.index[target]
+	if !ok {
+		return Edge[T]{}, ErrorEdgeNotFound
+	}
+
+	e := f.find(i, j)
+	if e < 0 {
+		return Edge[T]{}, ErrorEdgeNotFound
+	}
+
+	return Edge[T]{
+		Source: f.values[i],
+		Target: f.values[j],
+		Properties: EdgeProperties{
+			Weight:      f.outWeights[e],
+			FloatWeight: f.outFloatWeights[e],
+			Attributes:  f.outAttributes[e],
+			Data:        f.outData[e],
+			ID:          f.outIDs[e],
+			Type:        f.outTypes[e],
+		},
+	}, nil
+}
+
+func (f *Frozen[K, T]) Edges() ([]Edge[K], error) {
+	res := make([]Edge[K], 0, len(f.outTargets))
+
+	for source := range f.keys {
+		for e := f.outOffsets[source]; e < f.outOffsets[source+1]; e++ {
+			// Ненаправленная дуга хранится в обе стороны, отдаём одну
+			if !f.traits.IsDirected && f.outTargets[e] < source {
+				continue
+			}
+			res = append(res, f.edge(source, e))
+		}
+	}
+
+	return res, nil
+}
+
+func (f *Frozen[K, T]) AdjacencyMap() (map[K]map[K]Edge[K], error) {
+	m := make(map[K]map[K]Edge[K], len(f.keys))
+
+	for source, hash := range f.keys {
+		m[hash] = make(map[K]Edge[K], f.outOffsets[source+1]-f.outOffsets[source])
+		for e := f.outOffsets[source]; e < f.outOffsets[source+1]; e++ {
+			m[hash][f.keys[f.outTargets[e]]] = f.edge(source, e)
+		}
+	}
+
+	return m, nil
+}
+
+func (f *Frozen[K, T]) PredecessorMap() (map[K]map[K]Edge[K], error) {
+	if !f.traits.IsDirected {
+		return f.AdjacencyMap()
+	}
+
+	m := make(map[K]map[K]Edge[K], len(f.keys))
+
+	for target, hash := range f.keys {
+		m[hash] = make(map[K]Edge[K], f.inOffsets[target+1]-f.inOffsets[target])
+		for p := f.inOffsets[target]; p < f.inOffsets[target+1]; p++ {
+			source := f.inSources[p]
+			m[hash][f.keys[source]] = f.edge(source, f.inEdges[p])
+		}
+	}
+
+	return m, nil
+}
+
+func (f *Frozen[K, T]) Neighbors(hash K) ([]K, error) {
+	i, ok := f.index[hash]
+	if !ok {
+		return nil, ErrorVertextNotFound
+	}
+
+	res := make([]K, 0, f.outOffsets[i+1]-f.outOffsets[i])
+	for _, j := range f.NeighborIndexes(i) {
+		res = append(res, f.keys[j])
+	}
+
+	return res, nil
+}
+
+func (f *Frozen[K, T]) Predecessors(hash K) ([]K, error) {
+	i, ok := f.index[hash]
+	if !ok {
+		return nil, ErrorVertextNotFound
+	}
+
+	res := make([]K, 0, f.inOffsets[i+1]-f.inOffsets[i])
+	for _, j := range f.inSources[f.inOffsets[i]:f.inOffsets[i+1]] {
+		res = append(res, f.keys[j])
+	}
+
+	return res, nil
+}
+
+func (f *Frozen[K, T]) OutEdges(hash K) ([]Edge[K], error) {
+	i, ok := f.index[hash]
+	if !ok {
+		return nil, ErrorVertextNotFound
+	}
+
+	res := make([]Edge[K], 0, f.outOffsets[i+1]-f.outOffsets[i])
+	for e := f.outOffsets[i]; e < f.outOffsets[i+1]; e++ {
+		res = append(res, f.edge(i, e))
+	}
+
+	return res, nil
+}
+
+func (f *Frozen[K, T]) InEdges(hash K) ([]Edge[K], error) {
+	i, ok := f.index[hash]
+	if !ok {
+		return nil, ErrorVertextNotFound
+	}
+
+	res := make([]Edge[K], 0, f.inOffsets[i+1]-f.inOffsets[i])
+	for p := f.inOffsets[i]; p < f.inOffsets[i+1]; p++ {
+		res = append(res, f.edge(f.inSources[p], f.inEdges[p]))
+	}
+
+	return res, nil
+}
+
+// Снимок неизменяемый, поэтому копия не нужна
+func (f *Frozen[K, T]) Clone() (Graph[K, T], error) {
+	return f, nil
+}
+
+func (f *Frozen[K, T]) Order() (int, error) {
+	return len(f.keys), nil
+}
+
+func (f *Frozen[K, T]) Size() (int, error) {
+	if !f.traits.IsDirected {
+		return (len(f.outTargets) + f.loopCount) / 2, nil
+	}
+
+	return len(f.outTargets), nil
+}
+
+func (f *Frozen[K, T]) InDegree(hash K) (int, error) {
+	if !f.traits.IsDirected {
+		return f.OutDegree(hash)
+	}
+
+	i, ok := f.index[hash]
+	if !ok {
+		return 0, ErrorVertextNotFound
+	}
+
+	return f.inOffsets[i+1] - f.inOffsets[i], nil
+}
+
+func (f *Frozen[K, T]) OutDegree(hash K) (int, error) {
+	i, ok := f.index[hash]
+	if !ok {
+		return 0, ErrorVertextNotFound
+	}
+
+	return f.outOffsets[i+1] - f.outOffsets[i], nil
+}
+
+// Как у ненаправленного графа, петля в степени считается дважды
+func (f *Frozen[K, T]) Degree(hash K) (int, error) {
+	out, err := f.OutDegree(hash)
+	if err != nil {
+		return 0, err
+	}
+
+	if !f.traits.IsDirected {
+		return out + f.loops[f.index[hash]], nil
+	}
+
+	in, err := f.InDegree(hash)
+	if err != nil {
+		return 0, err
+	}
+
+	return in + out, nil
+}
+
+func (f *Frozen[K, T]) hashFunc() Hash[K, T] {
+	return f.hash
+}
+
+// У снимка нет хранилища, NewLike создаст граф в памяти
+func (f *Frozen[K, T]) storage() Store[K, T] {
+	return nil
+}
@@ -0,0 +1,176 @@
+package graph
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFreezeMatchesGraph(t *testing.T) {
+	g := directedGraph(t, 5, [][2]int{{1, 2}, {2, 3}, {3, 1}, {4, 4}, {3, 5}})
+
+	f, err := Freeze(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := edgeList(t, f), edgeList(t, g); !reflect.DeepEqual(got, want) {
+		t.Fatalf("дуги снимка %v, ожидалось %v", got, want)
+	}
+
+	for v := 1; v <= 5; v++ {
+		got, err := f.Neighbors(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := g.Neighbors(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := sortedCopy(got), sortedCopy(want); !slices.Equal(got, want) {
+			t.Fatalf("соседи %d: %v, ожидалось %v", v, got, want)
+		}
+	}
+
+	// Запись в исходный граф снимок не видит
+	if err := g.AddEdge(5, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Edge(5, 1); err == nil {
+		t.Fatal("дуга, добавленная после Freeze, попала в снимок")
+	}
+}
+
+// Запускать с -race: писатели добавляют и убирают вершину вместе с её дугами
+// одним Batch, поэтому в любом снимке у каждой вершины k > 0 есть обе дуги 0 <-> k.
+// Фоновые вершины без дуг растягивают чтение, чтобы запись успела вклиниться
+func TestFreezeConcurrentWrites(t *testing.T) {
+	graphs := []struct {
+		name string
+		new  func() Graph[int, int]
+	}{
+		{"memory", func() Graph[int, int] { return New(IntHash, Directed()) }},
+		{"sharded", func() Graph[int, int] {
+			return NewWithStore(IntHash, Store[int, int](NewShardedStore[int, int](4)), Directed())
+		}},
+		{"dense", func() Graph[int, int] { return NewDenseInt(IntHash, Directed()) }},
+		{"undirected", func() Graph[int, int] { return New[int, int](IntHash) }},
+		{"history", func() Graph[int, int] { return NewHistory(New(IntHash, Directed())) }},
+		{"typed", func() Graph[int, int] { return NewTyped[int, int, route](New(IntHash, Directed())) }},
+	}
+
+	const (
+		churn      = 16
+		background = 300
+		writers    = 2
+		freezes    = 50
+		toggles    = 1000
+	)
+
+	for _, test := range graphs {
+		t.Run(test.name, func(t *testing.T) {
+			g := test.new()
+			for v := 0; v <= background; v++ {
+				if err := g.AddVertex(1000 + v); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := g.AddVertex(0); err != nil {
+				t.Fatal(err)
+			}
+
+			var wg sync.WaitGroup
+			var toggled atomic.Int64
+			stop := make(chan struct{})
+
+			for w := 0; w < writers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+
+					for i := 0; ; i++ {
+						select {
+						case <-stop:
+							return
+						default:
+						}
+
+						k := 1 + w*churn + i%churn
+						if err := toggleSpoke(g, k); err != nil {
+							t.Error(err)
+							return
+						}
+						toggled.Add(1)
+					}
+				}(w)
+			}
+
+			// Снимаем, пока писатели не сделают достаточно изменений
+			for i := 0; (i < freezes || toggled.Load() < toggles) && !t.Failed(); i++ {
+				f, err := Freeze(g)
+				if err != nil {
+					close(stop)
+					wg.Wait()
+					t.Fatal(err)
+				}
+
+				adjacency, err := f.AdjacencyMap()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				spokes := 0
+				for k := range adjacency {
+					if k == 0 || k >= 1000 {
+						continue
+					}
+					spokes++
+
+					if _, ok := adjacency[0][k]; !ok {
+						t.Fatalf("в снимке есть вершина %d без дуги 0 -> %d", k, k)
+					}
+					if _, ok := adjacency[k][0]; !ok {
+						t.Fatalf("в снимке есть вершина %d без дуги %d -> 0", k, k)
+					}
+				}
+
+				if len(adjacency[0]) != spokes {
+					t.Fatalf("у вершины 0 дуг %d, а вершин при ней %d", len(adjacency[0]), spokes)
+				}
+			}
+
+			close(stop)
+			wg.Wait()
+		})
+	}
+}
+
+// Добавляет вершину k с дугами 0 <-> k или убирает её, всё одним Batch
+func toggleSpoke(g Graph[int, int], k int) error {
+	return g.Batch(func(tx Graph[int, int]) error {
+		if _, err := tx.Vertex(k); err == nil {
+			if err := tx.RemoveEdge(0, k); err != nil {
+				return err
+			}
+			if tx.Traits().IsDirected {
+				if err := tx.RemoveEdge(k, 0); err != nil {
+					return err
+				}
+			}
+			return tx.RemoveVertex(k)
+		}
+
+		if err := tx.AddVertex(k); err != nil {
+			return err
+		}
+		if err := tx.AddEdge(0, k); err != nil {
+			return err
+		}
+		if !tx.Traits().IsDirected {
+			return nil
+		}
+		return tx.AddEdge(k, 0)
+	})
+}
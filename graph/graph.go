@@ -1,6 +1,18 @@
 package graph
 
+import "slices"
+
 // Структура нашего графа
+//
+// Работа из нескольких горутин. Каждый метод графа атомарен относительно хранилища:
+// изменение либо видно целиком, либо не видно совсем, а чтения вроде Edge или
+// AdjacencyMap видят согласованное состояние на какой-то момент между вызовом и возвратом.
+// Несколько изменений подряд атомарными не становятся, для этого есть Batch.
+// Долгим алгоритмам, которым нужен неизменный граф, пока другие пишут, нужен Snapshot.
+// Traits это настройка графа, менять их поля одновременно с работой графа нельзя.
+// Гарантии дают хранилища с ViewStore и BatchStore, как MemoryStore, DenseIntStore, FileStore
+// и ShardedStore. ShardedStore ещё и пишет дуги между разными вершинами параллельно.
+// Для своего хранилища без них методы только откатываются при ошибке, а за блокировки отвечает само хранилище
 type Graph[K comparable, T any] interface {
 	// Для указания какой граф будет
 	// Передается через интерфейс Traits при инициализации гарфа
@@ -12,8 +24,15 @@ type Graph[K comparable, T any] interface {
 	Vertex(hash K) (T, error)
 	// Удаляет вершину
 	RemoveVertex(hash K) error
+	// Удаляет вершину вместе со всеми её дугами одной операцией.
+	// Возвращает удалённые дуги
+	RemoveVertexCascade(hash K) ([]Edge[K], error)
+	// Обновляет свойства вершины, как EditEdge для дуги
+	EditVertex(hash K, options ...func(*VertexProperties)) error
 	// Добавляет новую дугу
 	AddEdge(sorce, target K, options ...func(*EdgeProperties)) error
+	// Как AddEdge, но возвращает ID новой дуги. В обычном графе ID всегда 0
+	AddEdgeWithID(source, target K, options ...func(*EdgeProperties)) (uint64, error)
 	// Выводит дугу содененную двумя вершинами soruce и target
 	Edge(source, target K) (Edge[T], error)
 	// Возвращает все дуги графа
@@ -23,23 +42,69 @@ type Graph[K comparable, T any] interface {
 	// Удаляет дугу между дух вершин source и target
 	RemoveEdge(source, target K) error
 
+	// Все дуги между source и target. В мультиграфе их может быть несколько,
+	// а Edge, EditEdge и RemoveEdge работают со всей парой: Edge отдаёт первую дугу,
+	// EditEdge и RemoveEdge меняют и удаляют все
+	EdgesBetween(source, target K) ([]Edge[K], error)
+	// Дуга мультиграфа по ID
+	EdgeByID(id uint64) (Edge[K], error)
+	// Обновляет одну дугу мультиграфа
+	EditEdgeByID(id uint64, options ...func(properties *EdgeProperties)) error
+	// Удаляет одну дугу мультиграфа
+	RemoveEdgeByID(id uint64) error
+
 	// Дополнительные функции для копирования существуюшего графа в новый
 	AddVerticesFrom(g Graph[K, T]) error
 	AddEdgesFrom(g Graph[K, T]) error
 
-	// Карта смежности
+	// Карта смежности. Из параллельных дуг в ней только одна
 	AdjacencyMap() (map[K]map[K]Edge[K], error)
+	// Соседи вершины: куда ведут дуги из hash.
+	// В отличие от AdjacencyMap читает хранилище напрямую, без копии всего графа
+	Neighbors(hash K) ([]K, error)
+	// Вершины, из которых ведут дуги в hash
+	Predecessors(hash K) ([]K, error)
+	// Дуги, выходящие из вершины
+	OutEdges(hash K) ([]Edge[K], error)
+	// Дуги, входящие в вершину
+	InEdges(hash K) ([]Edge[K], error)
+	// Карта предшественников: target -> source.
+	// Для ненаправленного графа совпадает с картой смежности
+	PredecessorMap() (map[K]map[K]Edge[K], error)
 	// Возврашает вершину с его доп переменными
 	VertexWithProperties(hash K) (T, VertexProperties, error)
 	// Дополнительные функции
-	// Клонирование графа
+	// Клонирование графа. Копия графа поверх FileStore живёт в памяти:
+	// новому файловому хранилищу нужен свой каталог
 	Clone() (Graph[K, T], error)
 
+	// Выполняет группу изменений атомарно.
+	// Все изменения через tx видны сразу, но при ошибке fn откатываются
+	Batch(fn func(tx Graph[K, T]) error) error
+	// Снимок графа только для чтения. Изменения графа после вызова в нём не видны.
+	// Снимок нужно отпустить через Release
+	Snapshot() (*Snapshot[K, T], error)
+
+	// Регистрирует слушателя изменений. Он вызывается до применения изменения
+	// под блокировкой хранилища и может запретить изменение ошибкой,
+	// поэтому вызывать методы графа из слушателя нельзя. cancel снимает слушателя
+	Observe(listener Listener[K]) (cancel func())
+	// Асинхронная подписка на применённые изменения. События Batch приходят
+	// только после его успеха. cancel закрывает канал
+	Subscribe(buffer int) (events <-chan Event[K], cancel func())
+
 	// Функция возврата количества вершин в графе
 	Order() (int, error)
 
 	// Функци возврата количества дуг в графе
 	Size() (int, error)
+
+	// Полустепень захода вершины
+	InDegree(hash K) (int, error)
+	// Полустепень исхода вершины
+	OutDegree(hash K) (int, error)
+	// Степень вершины. Для направленного графа это сумма полустепеней
+	Degree(hash K) (int, error)
 }
 
 // Дополнительная структура данных, представляемые динамические параметры вершины
@@ -48,6 +113,10 @@ type Graph[K comparable, T any] interface {
 type VertexProperties struct {
 	Attributes map[string]string
 	Weight     int
+	// Дробный вес для графа FloatWeighted. Граф держит его равным Weight
+	FloatWeight float64
+	// Метки вершины, например "Person" или "Repo". Без повторов, по возрастанию
+	Labels []string
 }
 
 // Структура дуги
@@ -64,13 +133,97 @@ type Edge[T any] struct {
 type EdgeProperties struct {
 	Attributes map[string]string
 	Weight     int
-	Data       any
+	// Дробный вес для графа FloatWeighted, например расстояние в километрах.
+	// Граф держит его равным Weight
+	FloatWeight float64
+	Data        any
+	// Номер дуги в мультиграфе, различает параллельные дуги.
+	// Назначается графом при добавлении и больше не меняется. В обычном графе 0
+	ID uint64
+	// Тип связи, например "OWNS" или "FOLLOWS". Пустой у дуги без типа
+	Type string
+}
+
+// Есть ли у вершины метка label
+func (p VertexProperties) HasLabel(label string) bool {
+	return slices.Contains(p.Labels, label)
+}
+
+// Числовые типы, которыми можно задать вес
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
 }
 
 // Весы для графа
 func EdgeWeight(weight int) func(*EdgeProperties) {
 	return func(e *EdgeProperties) {
 		e.Weight = weight
+		e.FloatWeight = float64(weight)
+	}
+}
+
+// Дробный вес дуги, например расстояние в километрах
+func EdgeWeightFloat(weight float64) func(*EdgeProperties) {
+	return EdgeWeightOf(weight)
+}
+
+// Вес дуги из int64, например времени в наносекундах
+func EdgeWeightInt64(weight int64) func(*EdgeProperties) {
+	return EdgeWeightOf(weight)
+}
+
+// Вес дуги любого числового типа, например EdgeWeightOf(12.5) для километров.
+// Дробную часть сохраняет только граф FloatWeighted, в Weight попадает целая
+func EdgeWeightOf[W Number](weight W) func(*EdgeProperties) {
+	return func(e *EdgeProperties) {
+		e.Weight = int(weight)
+		e.FloatWeight = float64(weight)
+	}
+}
+
+// Вес вершины любого числового типа, дробный так же, как у EdgeWeightOf
+func VertexWeight[W Number](weight W) func(*VertexProperties) {
+	return func(p *VertexProperties) {
+		p.Weight = int(weight)
+		p.FloatWeight = float64(weight)
+	}
+}
+
+// Добавляет вершине метки
+func VertexLabels(labels ...string) func(*VertexProperties) {
+	return func(p *VertexProperties) {
+		// Новый срез, старый может быть общим с хранилищем
+		merged := make([]string, 0, len(p.Labels)+len(labels))
+		merged = append(merged, p.Labels...)
+		merged = append(merged, labels...)
+
+		slices.Sort(merged)
+		p.Labels = slices.Compact(merged)
+	}
+}
+
+// Убирает у вершины метки, например в EditVertex
+func DropVertexLabels(labels ...string) func(*VertexProperties) {
+	return func(p *VertexProperties) {
+		p.Labels = slices.DeleteFunc(slices.Clone(p.Labels), func(label string) bool {
+			return slices.Contains(labels, label)
+		})
+	}
+}
+
+// Тип связи дуги
+func EdgeType(edgeType string) func(*EdgeProperties) {
+	return func(e *EdgeProperties) {
+		e.Type = edgeType
+	}
+}
+
+// Свой номер дуги мультиграфа вместо выданного графом
+func EdgeID(id uint64) func(*EdgeProperties) {
+	return func(e *EdgeProperties) {
+		e.ID = id
 	}
 }
 
@@ -78,6 +231,12 @@ func EdgeWeight(weight int) func(*EdgeProperties) {
 type Hash[K comparable, T any] func(T) K
 
 func New[K comparable, T any](hash Hash[K, T], options ...func(*Traits)) Graph[K, T] {
+	return NewWithStore(hash, NewMemoryStore[K, T](), options...)
+}
+
+// Создание графа поверх своего хранилища.
+// Хранилище должно быть пустым или уже содержать данные графа того же вида
+func NewWithStore[K comparable, T any](hash Hash[K, T], store Store[K, T], options ...func(*Traits)) Graph[K, T] {
 	var t Traits
 
 	for _, option := range options {
@@ -85,29 +244,60 @@ func New[K comparable, T any](hash Hash[K, T], options ...func(*Traits)) Graph[K
 	}
 
 	if t.IsDirected {
-		return newDirected(hash, &t, newStore[K, T]())
+		return newDirected(hash, &t, store)
 	}
 
-	return newUndirected(hash, &t, newStore[K, T]())
+	return newUndirected(hash, &t, store)
 }
 
-// Функция создания нового графа по входному другому графу
+// Функция создания нового графа по входному другому графу.
+// Новый граф пустой, но использует тот же вид хранилища.
+// Хранилище без StoreFactory (например, FileStore) даёт граф в памяти.
+// Для графа не из этого пакета хранилище тоже в памяти, а хэш берётся по hashOf
 func NewLike[K comparable, T any](g Graph[K, T]) Graph[K, T] {
+	root := currentRoot(g)
 	copyTraits := func(t *Traits) {
 		t.IsDirected = g.Traits().IsDirected
 		t.IsRooted = g.Traits().IsRooted
+		t.IsTree = g.Traits().IsTree
+		t.IsMultigraph = g.Traits().IsMultigraph
 		t.IsWeighted = g.Traits().IsWeighted
+		t.IsFloatWeighted = g.Traits().IsFloatWeighted
+		t.root = root
 	}
 
-	var hash Hash[K, T]
+	return NewWithStore(hashOf(g), emptyLike(storeOf(g)), copyTraits)
+}
+
+// Доступ к хэш-функции и хранилищу графа для функций пакета
+type storeGraph[K comparable, T any] interface {
+	hashFunc() Hash[K, T]
+	storage() Store[K, T]
+}
+
+// Хэш-функция графа g. Если граф её не раскрывает, а T совпадает с K,
+// ключом служит само значение. Иначе nil, и AddVertex вернёт ErrorHashUnknown
+func hashOf[K comparable, T any](g Graph[K, T]) Hash[K, T] {
+	if inner, ok := g.(storeGraph[K, T]); ok {
+		return inner.hashFunc()
+	}
+
+	if _, ok := any(*new(T)).(K); ok {
+		return func(value T) K {
+			return any(value).(K)
+		}
+	}
+
+	return nil
+}
 
-	if g.Traits().IsDirected {
-		hash = g.(*directed[K, T]).hash
-	} else {
-		hash = g.(*undirected[K, T]).hash
+// Хранилище графа g или nil, если граф его не раскрывает
+func storeOf[K comparable, T any](g Graph[K, T]) Store[K, T] {
+	if inner, ok := g.(storeGraph[K, T]); ok {
+		return inner.storage()
 	}
 
-	return New(hash, copyTraits)
+	return nil
 }
 
 // Функции определения чем заполняется граф
@@ -0,0 +1,110 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+)
+
+// Граф не из этого пакета: хэш и хранилище внутреннего графа не видны
+type foreignGraph[K comparable, T any] struct {
+	Graph[K, T]
+}
+
+func TestNewLikeForeignGraph(t *testing.T) {
+	source := &foreignGraph[int, int]{New(IntHash, Directed())}
+
+	// Ключ совпадает со значением, хэш берётся тождественный
+	like := NewLike[int, int](source)
+	if !like.Traits().IsDirected {
+		t.Fatal("NewLike потерял направленность")
+	}
+	if _, ok := storeOf(like).(*MemoryStore[int, int]); !ok {
+		t.Fatalf("NewLike дал хранилище %T, ожидалось в памяти", storeOf(like))
+	}
+	if err := like.AddVertex(7); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := like.Vertex(7); err != nil {
+		t.Fatal(err)
+	}
+
+	history := NewHistory[int, int](source)
+	if err := history.AddVertex(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := history.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.Vertex(1); !errors.Is(err, ErrorVertextNotFound) {
+		t.Fatalf("после Undo вершина 1 осталась: %v", err)
+	}
+
+	persistent, err := NewPersistentFrom[int, int](source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order, _ := persistent.Order(); order != 0 {
+		t.Fatalf("Order() = %d, ожидалось 0", order)
+	}
+}
+
+func TestNewLikeUnknownHash(t *testing.T) {
+	length := func(s string) int {
+		return len(s)
+	}
+	source := &foreignGraph[int, string]{New(length)}
+	if err := source.AddVertex("abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ключ из строки не получить, поэтому ошибки вместо паники
+	like := NewLike[int, string](source)
+	if err := like.AddVertex("abc"); !errors.Is(err, ErrorHashUnknown) {
+		t.Fatalf("AddVertex вернул %v, ожидалось ErrorHashUnknown", err)
+	}
+
+	if _, err := NewPersistentFrom[int, string](source); !errors.Is(err, ErrorHashUnknown) {
+		t.Fatalf("NewPersistentFrom вернул %v, ожидалось ErrorHashUnknown", err)
+	}
+
+	history := NewHistory[int, string](source)
+	if err := history.AddVertex("de"); !errors.Is(err, ErrorHashUnknown) {
+		t.Fatalf("History.AddVertex вернул %v, ожидалось ErrorHashUnknown", err)
+	}
+	if _, err := source.Vertex(2); !errors.Is(err, ErrorVertextNotFound) {
+		t.Fatal("вершина без записи в истории добавилась")
+	}
+}
+
+func TestNewLikeTyped(t *testing.T) {
+	dense := NewWithStore(IntHash, Store[int, int](NewDenseIntStore[int](DenseMaxKey(8))), Directed())
+	typed := NewTyped[int, int, route](dense)
+
+	like := NewLike[int, int](typed)
+	if _, ok := storeOf(like).(*DenseIntStore[int]); !ok {
+		t.Fatalf("NewLike дал хранилище %T, ожидалось DenseIntStore", storeOf(like))
+	}
+}
+
+func TestCloneFileStore(t *testing.T) {
+	s := openFileStore(t, t.TempDir())
+	defer s.Close()
+
+	g := NewWithStore(IntHash, Store[int, int](s), Directed())
+	if err := g.AddVertex(1); err != nil {
+		t.Fatal(err)
+	}
+
+	clone, err := g.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// У FileStore нет StoreFactory, копия живёт в памяти
+	if _, ok := storeOf(clone).(*MemoryStore[int, int]); !ok {
+		t.Fatalf("Clone дал хранилище %T, ожидалось в памяти", storeOf(clone))
+	}
+	if _, err := clone.Vertex(1); err != nil {
+		t.Fatal(err)
+	}
+}
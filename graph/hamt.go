@@ -0,0 +1,255 @@
+package graph
+
+import "math/bits"
+
+// Неизменяемая карта на префиксном дереве с битовыми масками
+// (hash array mapped trie). set и delete возвращают новую карту,
+// которая делит со старой все узлы, кроме пути от корня до изменённого ключа
+type hamt[K comparable, V any] struct {
+	root *hamtNode[K, V]
+	size int
+	hash func(K) uint64
+}
+
+// По 5 бит хэша на уровень, то есть до 32 детей у узла
+const (
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+)
+
+// Узел дерева. entries лежат по порядку установленных битов bitmap.
+// Когда биты хэша кончились, узел хранит коллизии простым списком
+type hamtNode[K comparable, V any] struct {
+	bitmap  uint32
+	entries []hamtEntry[K, V]
+}
+
+// Либо пара ключ-значение, либо поддерево в node
+type hamtEntry[K comparable, V any] struct {
+	hash  uint64
+	key   K
+	value V
+	node  *hamtNode[K, V]
+}
+
+func newHamt[K comparable, V any](hash func(K) uint64) hamt[K, V] {
+	return hamt[K, V]{hash: hash}
+}
+
+func (m hamt[K, V]) len() int {
+	return m.size
+}
+
+func (m hamt[K, V]) get(key K) (V, bool) {
+	var empty V
+	if m.root == nil {
+		return empty, false
+	}
+
+	h := m.hash(key)
+	node := m.root
+
+	for shift := 0; ; shift += hamtBits {
+		if shift >= 64 {
+			for _, entry := range node.entries {
+				if entry.key == key {
+					return entry.value, true
+				}
+			}
+			return empty, false
+		}
+
+		bit := uint32(1) << ((h >> shift) & hamtMask)
+		if node.bitmap&bit == 0 {
+			return empty, false
+		}
+
+		entry := node.entries[bits.OnesCount32(node.bitmap&(bit-1))]
+		if entry.node == nil {
+			if entry.key == key {
+				return entry.value, true
+			}
+			return empty, false
+		}
+
+		node = entry.node
+	}
+}
+
+func (m hamt[K, V]) set(key K, value V) hamt[K, V] {
+	root, added := hamtSet(m.root, 0, hamtEntry[K, V]{hash: m.hash(key), key: key, value: value})
+
+	m.root = root
+	if added {
+		m.size++
+	}
+
+	return m
+}
+
+func (m hamt[K, V]) delete(key K) hamt[K, V] {
+	if m.root == nil {
+		return m
+	}
+
+	root, removed := hamtDelete(m.root, 0, m.hash(key), key)
+	if !removed {
+		return m
+	}
+
+	m.root = root
+	m.size--
+
+	return m
+}
+
+// Обходит все пары, пока fn возвращает true
+func (m hamt[K, V]) each(fn func(key K, value V) bool) {
+	if m.root != nil {
+		m.root.each(fn)
+	}
+}
+
+func (n *hamtNode[K, V]) each(fn func(key K, value V) bool) bool {
+	for _, entry := range n.entries {
+		if entry.node != nil {
+			if !entry.node.each(fn) {
+				return false
+			}
+			continue
+		}
+
+		if !fn(entry.key, entry.value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Копия узла с новым entries. Старый узел может принадлежать другой версии карты
+func (n *hamtNode[K, V]) with(entries []hamtEntry[K, V]) *hamtNode[K, V] {
+	return &hamtNode[K, V]{bitmap: n.bitmap, entries: entries}
+}
+
+// Добавляет или заменяет пару в поддереве node. Возвращает новый узел и признак новой пары
+func hamtSet[K comparable, V any](node *hamtNode[K, V], shift int, leaf hamtEntry[K, V]) (*hamtNode[K, V], bool) {
+	if node == nil {
+		node = &hamtNode[K, V]{}
+	}
+
+	if shift >= 64 {
+		entries := make([]hamtEntry[K, V], len(node.entries), len(node.entries)+1)
+		copy(entries, node.entries)
+
+		for i, entry := range entries {
+			if entry.key == leaf.key {
+				entries[i] = leaf
+				return node.with(entries), false
+			}
+		}
+
+		return node.with(append(entries, leaf)), true
+	}
+
+	bit := uint32(1) << ((leaf.hash >> shift) & hamtMask)
+	i := bits.OnesCount32(node.bitmap & (bit - 1))
+
+	if node.bitmap&bit == 0 {
+		entries := make([]hamtEntry[K, V], 0, len(node.entries)+1)
+		entries = append(entries, node.entries[:i]...)
+		entries = append(entries, leaf)
+		entries = append(entries, node.entries[i:]...)
+
+		res := node.with(entries)
+		res.bitmap |= bit
+
+		return res, true
+	}
+
+	entries := make([]hamtEntry[K, V], len(node.entries))
+	copy(entries, node.entries)
+	current := entries[i]
+
+	switch {
+	case current.node != nil:
+		child, added := hamtSet(current.node, shift+hamtBits, leaf)
+		entries[i] = hamtEntry[K, V]{node: child}
+		return node.with(entries), added
+
+	case current.key == leaf.key:
+		entries[i] = leaf
+		return node.with(entries), false
+	}
+
+	// Два ключа на одном месте, уводим обоих на уровень ниже
+	child, _ := hamtSet(nil, shift+hamtBits, current)
+	child, _ = hamtSet(child, shift+hamtBits, leaf)
+	entries[i] = hamtEntry[K, V]{node: child}
+
+	return node.with(entries), true
+}
+
+// Удаляет ключ из поддерева node. Пустой узел возвращается как nil
+func hamtDelete[K comparable, V any](node *hamtNode[K, V], shift int, hash uint64, key K) (*hamtNode[K, V], bool) {
+	if shift >= 64 {
+		for i, entry := range node.entries {
+			if entry.key == key {
+				return node.without(i, 0), true
+			}
+		}
+		return node, false
+	}
+
+	bit := uint32(1) << ((hash >> shift) & hamtMask)
+	if node.bitmap&bit == 0 {
+		return node, false
+	}
+
+	i := bits.OnesCount32(node.bitmap & (bit - 1))
+	current := node.entries[i]
+
+	if current.node == nil {
+		if current.key != key {
+			return node, false
+		}
+		return node.without(i, bit), true
+	}
+
+	child, removed := hamtDelete(current.node, shift+hamtBits, hash, key)
+	if !removed {
+		return node, false
+	}
+
+	if child == nil {
+		return node.without(i, bit), true
+	}
+
+	entries := make([]hamtEntry[K, V], len(node.entries))
+	copy(entries, node.entries)
+
+	// Поддерево из одной пары сворачиваем обратно в пару
+	if len(child.entries) == 1 && child.entries[0].node == nil {
+		entries[i] = child.entries[0]
+	} else {
+		entries[i] = hamtEntry[K, V]{node: child}
+	}
+
+	return node.with(entries), true
+}
+
+// Копия узла без i-й записи
+func (n *hamtNode[K, V]) without(i int, bit uint32) *hamtNode[K, V] {
+	if len(n.entries) == 1 {
+		return nil
+	}
+
+	entries := make([]hamtEntry[K, V], 0, len(n.entries)-1)
+	entries = append(entries, n.entries[:i]...)
+	entries = append(entries, n.entries[i+1:]...)
+
+	res := n.with(entries)
+	res.bitmap &^= bit
+
+	return res
+}
@@ -0,0 +1,288 @@
+package graph
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// Хэши разной силы: обычный, из трёх значений и одинаковый для всех ключей.
+// У двух последних ключи доходят до конца хэша и лежат списком коллизий
+var hamtHashes = []struct {
+	name string
+	hash func(int) uint64
+}{
+	{"mixed", keyHash[int]},
+	{"three", func(k int) uint64 { return uint64(k % 3) }},
+	{"constant", func(int) uint64 { return 42 }},
+}
+
+// Содержимое карты через each
+func hamtContents(m hamt[int, int]) map[int]int {
+	res := make(map[int]int)
+	m.each(func(key, value int) bool {
+		res[key] = value
+		return true
+	})
+
+	return res
+}
+
+func checkHamt(t *testing.T, m hamt[int, int], want map[int]int) {
+	t.Helper()
+
+	if m.len() != len(want) {
+		t.Fatalf("len() = %d, ожидалось %d", m.len(), len(want))
+	}
+
+	got := hamtContents(m)
+	if len(got) != len(want) {
+		t.Fatalf("each обошёл %d пар, ожидалось %d", len(got), len(want))
+	}
+
+	for key, value := range want {
+		if v, ok := m.get(key); !ok || v != value {
+			t.Fatalf("get(%d) = %d, %v, ожидалось %d", key, v, ok, value)
+		}
+		if got[key] != value {
+			t.Fatalf("each отдал %d для %d, ожидалось %d", got[key], key, value)
+		}
+	}
+}
+
+func TestHamtMatchesMap(t *testing.T) {
+	for _, h := range hamtHashes {
+		t.Run(h.name, func(t *testing.T) {
+			m := newHamt[int, int](h.hash)
+			want := make(map[int]int)
+			random := rand.New(rand.NewSource(1))
+
+			for i := 0; i < 3000; i++ {
+				key := random.Intn(200)
+
+				if random.Intn(3) == 0 {
+					m = m.delete(key)
+					delete(want, key)
+				} else {
+					m = m.set(key, i)
+					want[key] = i
+				}
+
+				if _, ok := m.get(-1 - key); ok {
+					t.Fatalf("get нашёл отсутствующий ключ %d", -1-key)
+				}
+			}
+
+			checkHamt(t, m, want)
+
+			for key := range want {
+				m = m.delete(key)
+			}
+			if m.len() != 0 || m.root != nil {
+				t.Fatalf("после удаления всех ключей len() = %d, корень %v", m.len(), m.root)
+			}
+		})
+	}
+}
+
+// Старые версии не видят изменений новых
+func TestHamtPersistence(t *testing.T) {
+	for _, h := range hamtHashes {
+		t.Run(h.name, func(t *testing.T) {
+			versions := []hamt[int, int]{newHamt[int, int](h.hash)}
+			states := []map[int]int{{}}
+
+			for i := 0; i < 100; i++ {
+				last := versions[len(versions)-1]
+				state := make(map[int]int, len(states[len(states)-1]))
+				for k, v := range states[len(states)-1] {
+					state[k] = v
+				}
+
+				key := i % 40
+				if i%4 == 3 {
+					last = last.delete(key)
+					delete(state, key)
+				} else {
+					last = last.set(key, i)
+					state[key] = i
+				}
+
+				versions = append(versions, last)
+				states = append(states, state)
+			}
+
+			for i, version := range versions {
+				checkHamt(t, version, states[i])
+			}
+		})
+	}
+}
+
+// Новая версия копирует только путь до изменённого ключа
+func TestHamtStructuralSharing(t *testing.T) {
+	m := newHamt[int, int](keyHash[int])
+	for i := 0; i < 1000; i++ {
+		m = m.set(i, i)
+	}
+
+	next := m.set(500, -1)
+	if next.root == m.root {
+		t.Fatal("set изменил корень старой версии на месте")
+	}
+	if len(next.root.entries) != len(m.root.entries) {
+		t.Fatalf("в корне %d записей, было %d", len(next.root.entries), len(m.root.entries))
+	}
+
+	changed := 0
+	for i := range m.root.entries {
+		if next.root.entries[i].node != m.root.entries[i].node {
+			changed++
+		}
+	}
+	if changed != 1 {
+		t.Fatalf("скопировано %d поддеревьев корня, ожидалось одно", changed)
+	}
+
+	// Удаление отсутствующего ключа и повторное удаление ничего не копируют
+	if same := m.delete(-1); same.root != m.root {
+		t.Fatal("delete отсутствующего ключа скопировал корень")
+	}
+	removed := m.delete(500)
+	if again := removed.delete(500); again.root != removed.root {
+		t.Fatal("повторный delete скопировал корень")
+	}
+}
+
+// Ключи с одинаковым хэшем хранятся списком и не затирают друг друга
+func TestHamtCollisions(t *testing.T) {
+	m := newHamt[int, int](func(int) uint64 { return 7 })
+
+	for i := 0; i < 10; i++ {
+		m = m.set(i, i)
+	}
+	m = m.set(3, 30)
+	checkHamt(t, m, map[int]int{0: 0, 1: 1, 2: 2, 3: 30, 4: 4, 5: 5, 6: 6, 7: 7, 8: 8, 9: 9})
+
+	before := m
+	for i := 0; i < 10; i += 2 {
+		m = m.delete(i)
+	}
+	checkHamt(t, m, map[int]int{1: 1, 3: 30, 5: 5, 7: 7, 9: 9})
+	checkHamt(t, before, map[int]int{0: 0, 1: 1, 2: 2, 3: 30, 4: 4, 5: 5, 6: 6, 7: 7, 8: 8, 9: 9})
+
+	if _, ok := m.get(4); ok {
+		t.Fatal("удалённый ключ 4 остался")
+	}
+}
+
+func TestPersistentVersions(t *testing.T) {
+	v0 := NewPersistent(IntHash, Directed())
+
+	v1, err := v0.WithVertex(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2, err := v1.WithVertex(2, VertexWeight(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v3, err := v2.WithEdge(1, 2, EdgeWeight(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v4, err := v3.WithEditedEdge(1, 2, EdgeWeight(6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v5, removed, err := v4.WithoutVertexCascade(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 || removed[0].Properties.Weight != 6 {
+		t.Fatalf("WithoutVertexCascade удалил %v", removed)
+	}
+
+	for i, test := range []struct {
+		version *Persistent[int, int]
+		order   int
+		weight  int
+	}{
+		{v0, 0, -1},
+		{v1, 1, -1},
+		{v2, 2, -1},
+		{v3, 2, 5},
+		{v4, 2, 6},
+		{v5, 1, -1},
+	} {
+		if order, _ := test.version.Order(); order != test.order {
+			t.Fatalf("версия %d: Order() = %d, ожидалось %d", i, order, test.order)
+		}
+
+		edge, err := test.version.Edge(1, 2)
+		if test.weight < 0 {
+			if !errors.Is(err, ErrorEdgeNotFound) {
+				t.Fatalf("версия %d: Edge(1, 2) вернул %v", i, err)
+			}
+			continue
+		}
+		if err != nil || edge.Properties.Weight != test.weight {
+			t.Fatalf("версия %d: вес дуги %d (%v), ожидалось %d", i, edge.Properties.Weight, err, test.weight)
+		}
+	}
+
+	// Ошибка в WithBatch не даёт новой версии и не трогает старую
+	failed := errors.New("откат")
+	next, err := v4.WithBatch(func(tx Graph[int, int]) error {
+		if err := tx.RemoveEdge(1, 2); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) || next != nil {
+		t.Fatalf("WithBatch вернул %v, %v", next, err)
+	}
+	if _, err := v4.Edge(1, 2); err != nil {
+		t.Fatalf("после отката дуга 1 -> 2 пропала: %v", err)
+	}
+
+	if err := v4.AddVertex(3); !errors.Is(err, ErrorGraphReadOnly) {
+		t.Fatalf("AddVertex вернул %v", err)
+	}
+	if err := v4.RemoveEdge(1, 2); !errors.Is(err, ErrorGraphReadOnly) {
+		t.Fatalf("RemoveEdge вернул %v", err)
+	}
+}
+
+func TestPersistentFrom(t *testing.T) {
+	g := directedGraph(t, 4, [][2]int{{1, 2}, {2, 3}, {3, 4}})
+
+	p, err := NewPersistentFrom(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Изменения исходного графа версию не трогают
+	if err := g.RemoveEdge(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if got := edgeList(t, p); len(got) != 3 {
+		t.Fatalf("дуги версии %v", got)
+	}
+	if !p.Traits().IsDirected {
+		t.Fatal("версия потеряла направленность")
+	}
+
+	// Версия неизменяемая, клон это она сама, а NewLike даёт изменяемый граф в памяти
+	if clone, err := p.Clone(); err != nil || clone != Graph[int, int](p) {
+		t.Fatalf("Clone вернул %v, %v", clone, err)
+	}
+
+	like := NewLike[int, int](p)
+	if _, ok := storeOf(like).(*MemoryStore[int, int]); !ok {
+		t.Fatalf("NewLike дал хранилище %T", storeOf(like))
+	}
+	if err := like.AddVertex(1); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,680 @@
+package graph
+
+import "sync"
+
+// Граф с историей изменений для отмены и повтора.
+// Каждый изменяющий вызов, а также весь Batch целиком, становится одним шагом истории.
+// Шаг хранит всё, что нужно для обратного действия: старые свойства дуги при EditEdge
+// и вершину со свойствами при RemoveVertex.
+//
+// Изменения в обход History, прямо в исходном графе, в историю не попадают,
+// и после них Undo может не суметь откатить шаг
+type History[K comparable, T any] struct {
+	Graph[K, T]
+
+	lock sync.Mutex
+	hash Hash[K, T]
+
+	undo []historyStep[K, T]
+	redo []historyStep[K, T]
+	// Имя точки -> длина undo в момент её создания
+	checkpoints map[string]int
+}
+
+// Одно изменение и всё нужное для его отмены
+type historyChange[K comparable, T any] struct {
+	kind EventKind
+
+	vertex           T
+	hash             K
+	vertexProperties VertexProperties
+	vertexBefore     VertexProperties
+
+	source K
+	target K
+	before EdgeProperties
+	after  EdgeProperties
+}
+
+type historyStep[K comparable, T any] []historyChange[K, T]
+
+// Оборачивает граф g историей. История начинается пустой
+func NewHistory[K comparable, T any](g Graph[K, T]) *History[K, T] {
+	return &History[K, T]{
+		Graph:       g,
+		hash:        hashOf(g),
+		checkpoints: make(map[string]int),
+	}
+}
+
+// Выполняет изменения fn одним шагом истории
+func (h *History[K, T]) record(fn func(tx *historyRecorder[K, T]) error) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	var step historyStep[K, T]
+
+	err := h.Graph.Batch(func(tx Graph[K, T]) error {
+		return fn(&historyRecorder[K, T]{Graph: tx, hash: h.hash, step: &step})
+	})
+	if err != nil || len(step) == 0 {
+		return err
+	}
+
+	h.undo = append(h.undo, step)
+
+	// Новое изменение отменяет ветку повтора вместе с её точками
+	h.redo = nil
+	for name, position := range h.checkpoints {
+		if position > len(h.undo)-1 {
+			delete(h.checkpoints, name)
+		}
+	}
+
+	return nil
+}
+
+func (h *History[K, T]) AddVertex(value T, options ...func(*VertexProperties)) error {
+	return h.record(func(tx *historyRecorder[K, T]) error {
+		return tx.AddVertex(value, options...)
+	})
+}
+
+func (h *History[K, T]) RemoveVertex(hash K) error {
+	return h.record(func(tx *historyRecorder[K, T]) error {
+		return tx.RemoveVertex(hash)
+	})
+}
+
+func (h *History[K, T]) RemoveVertexCascade(hash K) ([]Edge[K], error) {
+	var removed []Edge[K]
+
+	err := h.record(func(tx *historyRecorder[K, T]) error {
+		var err error
+		removed, err = tx.RemoveVertexCascade(hash)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return removed, nil
+}
+
+func (h *History[K, T]) EditVertex(hash K, options ...func(*VertexProperties)) error {
+	return h.record(func(tx *historyRecorder[K, T]) error {
+		return tx.EditVertex(hash, options...)
+	})
+}
+
+func (h *History[K, T]) AddEdge(source, target K, options ...func(*EdgeProperties)) error {
+	return h.record(func(tx *historyRecorder[K, T]) error {
+		return tx.AddEdge(source, target, options...)
+	})
+}
+
+func (h *History[K, T]) AddEdgeWithID(source, target K, options ...func(*EdgeProperties)) (uint64, error) {
+	var id uint64
+
+	err := h.record(func(tx *historyRecorder[K, T]) error {
+		var err error
+		id, err = tx.AddEdgeWithID(source, target, options...)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (h *History[K, T]) EditEdge(source, target K, options ...func(properties *EdgeProperties)) error {
+	return h.record(func(tx *historyRecorder[K, T]) error {
+		return tx.EditEdge(source, target, options...)
+	})
+}
+
+func (h *History[K, T]) EditEdgeByID(id uint64, options ...func(properties *EdgeProperties)) error {
+	return h.record(func(tx *historyRecorder[K, T]) error {
+		return tx.EditEdgeByID(id, options...)
+	})
+}
+
+func (h *History[K, T]) RemoveEdge(source, target K) error {
+	return h.record(func(tx *historyRecorder[K, T]) error {
+		return tx.RemoveEdge(source, target)
+	})
+}
+
+func (h *History[K, T]) RemoveEdgeByID(id uint64) error {
+	return h.record(func(tx *historyRecorder[K, T]) error {
+		return tx.RemoveEdgeByID(id)
+	})
+}
+
+func (h *History[K, T]) AddVerticesFrom(g Graph[K, T]) error {
+	return h.record(func(tx *historyRecorder[K, T]) error {
+		return tx.AddVerticesFrom(g)
+	})
+}
+
+func (h *History[K, T]) AddEdgesFrom(g Graph[K, T]) error {
+	return h.record(func(tx *historyRecorder[K, T]) error {
+		return tx.AddEdgesFrom(g)
+	})
+}
+
+func (h *History[K, T]) Batch(fn func(tx Graph[K, T]) error) error {
+	return h.record(func(tx *historyRecorder[K, T]) error {
+		return fn(tx)
+	})
+}
+
+// Есть ли шаг для отмены
+func (h *History[K, T]) CanUndo() bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return len(h.undo) > 0
+}
+
+// Есть ли шаг для повтора
+func (h *History[K, T]) CanRedo() bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return len(h.redo) > 0
+}
+
+// Отменяет последний шаг
+func (h *History[K, T]) Undo() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return h.undoStep()
+}
+
+// Повторяет последний отменённый шаг
+func (h *History[K, T]) Redo() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return h.redoStep()
+}
+
+// Запоминает текущее состояние под именем name. Точка с тем же именем перезаписывается
+func (h *History[K, T]) Checkpoint(name string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.checkpoints[name] = len(h.undo)
+}
+
+// Отменяет или повторяет шаги, пока граф не вернётся к точке name.
+// Если какой-то шаг не удался, граф остаётся на последнем удачном шаге
+func (h *History[K, T]) RevertTo(name string) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	position, ok := h.checkpoints[name]
+	if !ok {
+		return ErrorCheckpointNotFound
+	}
+
+	for len(h.undo) > position {
+		if err := h.undoStep(); err != nil {
+			return err
+		}
+	}
+
+	for len(h.undo) < position {
+		if err := h.redoStep(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *History[K, T]) undoStep() error {
+	if len(h.undo) == 0 {
+		return ErrorNothingToUndo
+	}
+
+	step := h.undo[len(h.undo)-1]
+
+	err := h.Graph.Batch(func(tx Graph[K, T]) error {
+		for i := len(step) - 1; i >= 0; i-- {
+			if err := step[i].revert(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, step)
+
+	return nil
+}
+
+func (h *History[K, T]) redoStep() error {
+	if len(h.redo) == 0 {
+		return ErrorNothingToRedo
+	}
+
+	step := h.redo[len(h.redo)-1]
+
+	err := h.Graph.Batch(func(tx Graph[K, T]) error {
+		for _, change := range step {
+			if err := change.apply(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, step)
+
+	return nil
+}
+
+// Повторяет изменение. Дуги мультиграфа находятся по ID, остальные по паре вершин
+func (c historyChange[K, T]) apply(g Graph[K, T]) error {
+	switch c.kind {
+	case VertexAdded:
+		return g.AddVertex(c.vertex, setVertexProperties(c.vertexProperties))
+	case VertexRemoved:
+		return g.RemoveVertex(c.hash)
+	case VertexEdited:
+		return g.EditVertex(c.hash, setVertexProperties(c.vertexProperties))
+	case EdgeAdded:
+		return g.AddEdge(c.source, c.target, setEdgeProperties(c.after))
+	case EdgeEdited:
+		if c.after.ID != 0 {
+			return g.EditEdgeByID(c.after.ID, setEdgeProperties(c.after))
+		}
+		return g.EditEdge(c.source, c.target, setEdgeProperties(c.after))
+	case EdgeRemoved:
+		if c.before.ID != 0 {
+			return g.RemoveEdgeByID(c.before.ID)
+		}
+		return g.RemoveEdge(c.source, c.target)
+	}
+
+	return nil
+}
+
+// Выполняет обратное изменение
+func (c historyChange[K, T]) revert(g Graph[K, T]) error {
+	switch c.kind {
+	case VertexAdded:
+		return g.RemoveVertex(c.hash)
+	case VertexRemoved:
+		return g.AddVertex(c.vertex, setVertexProperties(c.vertexProperties))
+	case VertexEdited:
+		return g.EditVertex(c.hash, setVertexProperties(c.vertexBefore))
+	case EdgeAdded:
+		if c.after.ID != 0 {
+			return g.RemoveEdgeByID(c.after.ID)
+		}
+		return g.RemoveEdge(c.source, c.target)
+	case EdgeEdited:
+		if c.before.ID != 0 {
+			return g.EditEdgeByID(c.before.ID, setEdgeProperties(c.before))
+		}
+		return g.EditEdge(c.source, c.target, setEdgeProperties(c.before))
+	case EdgeRemoved:
+		return g.AddEdge(c.source, c.target, setEdgeProperties(c.before))
+	}
+
+	return nil
+}
+
+// Заменяет свойства вершины копией properties
+func setVertexProperties(properties VertexProperties) func(*VertexProperties) {
+	return func(p *VertexProperties) {
+		p.Attributes = copyAttributes(properties.Attributes)
+		p.Weight = properties.Weight
+		p.FloatWeight = properties.FloatWeight
+		p.Labels = properties.Labels
+	}
+}
+
+// Заменяет свойства дуги копией properties, лишние атрибуты пропадают.
+// ID тоже переносится, так что повторно добавленная дуга мультиграфа получит прежний
+func setEdgeProperties(properties EdgeProperties) func(*EdgeProperties) {
+	return func(p *EdgeProperties) {
+		p.Attributes = copyAttributes(properties.Attributes)
+		p.Weight = properties.Weight
+		p.FloatWeight = properties.FloatWeight
+		p.Data = properties.Data
+		p.ID = properties.ID
+		p.Type = properties.Type
+	}
+}
+
+func (h *History[K, T]) hashFunc() Hash[K, T] {
+	return h.hash
+}
+
+func (h *History[K, T]) storage() Store[K, T] {
+	return storeOf(h.Graph)
+}
+
+func (h *History[K, T]) readView(fn func(view Graph[K, T]) error) error {
+	return readGraph(h.Graph, fn)
+}
+
+// Граф внутри шага истории. Изменения идут в граф Batch и дописываются в шаг
+type historyRecorder[K comparable, T any] struct {
+	Graph[K, T]
+
+	hash Hash[K, T]
+	step *historyStep[K, T]
+}
+
+func (r *historyRecorder[K, T]) AddVertex(value T, options ...func(*VertexProperties)) error {
+	// Без хэша шаг не записать, а изменение без шага нельзя отменить
+	if r.hash == nil {
+		return ErrorHashUnknown
+	}
+
+	if err := r.Graph.AddVertex(value, options...); err != nil {
+		return err
+	}
+
+	hash := r.hash(value)
+
+	_, properties, err := r.Graph.VertexWithProperties(hash)
+	if err != nil {
+		return err
+	}
+
+	*r.step = append(*r.step, historyChange[K, T]{
+		kind:             VertexAdded,
+		vertex:           value,
+		hash:             hash,
+		vertexProperties: properties,
+	})
+
+	return nil
+}
+
+func (r *historyRecorder[K, T]) RemoveVertex(hash K) error {
+	value, properties, err := r.Graph.VertexWithProperties(hash)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Graph.RemoveVertex(hash); err != nil {
+		return err
+	}
+
+	*r.step = append(*r.step, historyChange[K, T]{
+		kind:             VertexRemoved,
+		vertex:           value,
+		hash:             hash,
+		vertexProperties: properties,
+	})
+
+	return nil
+}
+
+// Дуги удаляются по одной через RemoveEdge записывающего графа,
+// поэтому шаг хранит их все и отмена вернёт вершину вместе с дугами
+func (r *historyRecorder[K, T]) RemoveVertexCascade(hash K) ([]Edge[K], error) {
+	if _, err := r.Graph.Vertex(hash); err != nil {
+		return nil, err
+	}
+
+	edges, err := r.Graph.OutEdges(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	// У направленного графа есть и входящие дуги, петли среди них уже учтены
+	if r.Traits().IsDirected {
+		in, err := r.Graph.InEdges(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, edge := range in {
+			if edge.Source != hash {
+				edges = append(edges, edge)
+			}
+		}
+	}
+
+	for _, edge := range edges {
+		// В мультиграфе RemoveEdge удалил бы сразу все параллельные дуги
+		remove := func() error { return r.RemoveEdge(edge.Source, edge.Target) }
+		if r.Traits().IsMultigraph {
+			remove = func() error { return r.RemoveEdgeByID(edge.Properties.ID) }
+		}
+
+		if err := remove(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.RemoveVertex(hash); err != nil {
+		return nil, err
+	}
+
+	return edges, nil
+}
+
+func (r *historyRecorder[K, T]) EditVertex(hash K, options ...func(*VertexProperties)) error {
+	_, before, err := r.Graph.VertexWithProperties(hash)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Graph.EditVertex(hash, options...); err != nil {
+		return err
+	}
+
+	_, after, err := r.Graph.VertexWithProperties(hash)
+	if err != nil {
+		return err
+	}
+
+	*r.step = append(*r.step, historyChange[K, T]{
+		kind:             VertexEdited,
+		hash:             hash,
+		vertexProperties: after,
+		vertexBefore:     before,
+	})
+
+	return nil
+}
+
+func (r *historyRecorder[K, T]) AddEdge(source, target K, options ...func(*EdgeProperties)) error {
+	_, err := r.AddEdgeWithID(source, target, options...)
+	return err
+}
+
+func (r *historyRecorder[K, T]) AddEdgeWithID(source, target K, options ...func(*EdgeProperties)) (uint64, error) {
+	id, err := r.Graph.AddEdgeWithID(source, target, options...)
+	if err != nil {
+		return 0, err
+	}
+
+	var edge Edge[K]
+	if id != 0 {
+		edge, err = r.Graph.EdgeByID(id)
+	} else {
+		var stored Edge[T]
+		stored, err = r.Graph.Edge(source, target)
+		edge.Properties = stored.Properties
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	*r.step = append(*r.step, historyChange[K, T]{
+		kind:   EdgeAdded,
+		source: source,
+		target: target,
+		after:  edge.Properties,
+	})
+
+	return id, nil
+}
+
+// В мультиграфе EditEdge меняет все дуги пары, шаг запоминает каждую
+func (r *historyRecorder[K, T]) EditEdge(source, target K, options ...func(properties *EdgeProperties)) error {
+	before, err := r.Graph.EdgesBetween(source, target)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Graph.EditEdge(source, target, options...); err != nil {
+		return err
+	}
+
+	after, err := r.Graph.EdgesBetween(source, target)
+	if err != nil {
+		return err
+	}
+
+	for _, previous := range before {
+		for _, edge := range after {
+			if edge.Properties.ID == previous.Properties.ID {
+				r.edited(previous, edge)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *historyRecorder[K, T]) EditEdgeByID(id uint64, options ...func(properties *EdgeProperties)) error {
+	before, err := r.Graph.EdgeByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Graph.EditEdgeByID(id, options...); err != nil {
+		return err
+	}
+
+	after, err := r.Graph.EdgeByID(id)
+	if err != nil {
+		return err
+	}
+
+	r.edited(before, after)
+
+	return nil
+}
+
+func (r *historyRecorder[K, T]) edited(before, after Edge[K]) {
+	*r.step = append(*r.step, historyChange[K, T]{
+		kind:   EdgeEdited,
+		source: before.Source,
+		target: before.Target,
+		before: before.Properties,
+		after:  after.Properties,
+	})
+}
+
+func (r *historyRecorder[K, T]) RemoveEdge(source, target K) error {
+	before, err := r.Graph.EdgesBetween(source, target)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Graph.RemoveEdge(source, target); err != nil {
+		return err
+	}
+
+	for _, edge := range before {
+		r.removed(edge)
+	}
+
+	return nil
+}
+
+func (r *historyRecorder[K, T]) RemoveEdgeByID(id uint64) error {
+	before, err := r.Graph.EdgeByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Graph.RemoveEdgeByID(id); err != nil {
+		return err
+	}
+
+	r.removed(before)
+
+	return nil
+}
+
+func (r *historyRecorder[K, T]) removed(edge Edge[K]) {
+	*r.step = append(*r.step, historyChange[K, T]{
+		kind:   EdgeRemoved,
+		source: edge.Source,
+		target: edge.Target,
+		before: edge.Properties,
+	})
+}
+
+// Как у графа, но через AddVertex записывающего графа
+func (r *historyRecorder[K, T]) AddVerticesFrom(g Graph[K, T]) error {
+	adjacencyMap, err := g.AdjacencyMap()
+	if err != nil {
+		return err
+	}
+
+	for hash := range adjacencyMap {
+		vertex, properties, err := g.VertexWithProperties(hash)
+		if err != nil {
+			return err
+		}
+
+		if err := r.AddVertex(vertex, copyVertexProperties(properties)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Как у графа, но через AddEdge записывающего графа
+func (r *historyRecorder[K, T]) AddEdgesFrom(g Graph[K, T]) error {
+	edges, err := g.Edges()
+	if err != nil {
+		return err
+	}
+
+	for _, edge := range edges {
+		if err := r.AddEdge(copyEdge(edge)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Вложенный Batch идёт в тот же шаг
+func (r *historyRecorder[K, T]) Batch(fn func(tx Graph[K, T]) error) error {
+	return fn(r)
+}
+
+func (r *historyRecorder[K, T]) hashFunc() Hash[K, T] {
+	return r.hash
+}
+
+func (r *historyRecorder[K, T]) storage() Store[K, T] {
+	return storeOf(r.Graph)
+}
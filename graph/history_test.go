@@ -0,0 +1,316 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func historyState(t *testing.T, h *History[int, int]) string {
+	t.Helper()
+
+	return storeState[int, int](t, storeOf[int, int](h))
+}
+
+// Шаги разных видов. Каждый шаг это один вызов History
+var historySteps = []struct {
+	name string
+	step func(h *History[int, int]) error
+}{
+	{"AddVertex", func(h *History[int, int]) error { return h.AddVertex(4, VertexWeight(4)) }},
+	{"AddEdge", func(h *History[int, int]) error { return h.AddEdge(1, 4, EdgeWeight(14), edgeAttribute("kind", "new")) }},
+	{"EditEdge", func(h *History[int, int]) error {
+		return h.EditEdge(1, 2, EdgeWeight(100), edgeAttribute("kind", "edited"))
+	}},
+	{"EditVertex", func(h *History[int, int]) error { return h.EditVertex(2, vertexAttribute("color", "blue")) }},
+	{"RemoveEdge", func(h *History[int, int]) error { return h.RemoveEdge(2, 3) }},
+	{"Batch", func(h *History[int, int]) error {
+		return h.Batch(func(tx Graph[int, int]) error {
+			if err := tx.AddVertex(5); err != nil {
+				return err
+			}
+			if err := tx.AddEdge(3, 1); err != nil {
+				return err
+			}
+			return tx.EditEdge(1, 4, EdgeWeight(41))
+		})
+	}},
+	{"RemoveVertex", func(h *History[int, int]) error { return h.RemoveVertex(5) }},
+}
+
+func historyGraph(t *testing.T, options ...func(*Traits)) *History[int, int] {
+	t.Helper()
+
+	g := New(IntHash, options...)
+	for i := 1; i <= 3; i++ {
+		if err := g.AddVertex(i, vertexAttribute("color", "red")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, edge := range [][2]int{{1, 2}, {2, 3}, {3, 3}} {
+		if err := g.AddEdge(edge[0], edge[1], EdgeWeight(10*edge[0]+edge[1]), edgeAttribute("kind", "old")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return NewHistory(g)
+}
+
+var historyKinds = []struct {
+	name    string
+	options []func(*Traits)
+}{
+	{"directed", []func(*Traits){Directed()}},
+	{"undirected", nil},
+	{"multigraph", []func(*Traits){Directed(), Multigraph()}},
+}
+
+func TestHistoryUndoRedo(t *testing.T) {
+	for _, kind := range historyKinds {
+		t.Run(kind.name, func(t *testing.T) {
+			h := historyGraph(t, kind.options...)
+			states := []string{historyState(t, h)}
+
+			for _, step := range historySteps {
+				if err := step.step(h); err != nil {
+					t.Fatalf("%s: %v", step.name, err)
+				}
+				states = append(states, historyState(t, h))
+			}
+
+			for i := len(states) - 1; i > 0; i-- {
+				if err := h.Undo(); err != nil {
+					t.Fatal(err)
+				}
+				if got := historyState(t, h); got != states[i-1] {
+					t.Fatalf("после отмены %s:\n%s\nожидалось:\n%s", historySteps[i-1].name, got, states[i-1])
+				}
+			}
+			if err := h.Undo(); !errors.Is(err, ErrorNothingToUndo) {
+				t.Fatalf("лишний Undo вернул %v", err)
+			}
+
+			for i := 1; i < len(states); i++ {
+				if err := h.Redo(); err != nil {
+					t.Fatal(err)
+				}
+				if got := historyState(t, h); got != states[i] {
+					t.Fatalf("после повтора %s:\n%s\nожидалось:\n%s", historySteps[i-1].name, got, states[i])
+				}
+			}
+			if err := h.Redo(); !errors.Is(err, ErrorNothingToRedo) {
+				t.Fatalf("лишний Redo вернул %v", err)
+			}
+		})
+	}
+}
+
+// Новый шаг после отмены отбрасывает ветку повтора вместе с её точками
+func TestHistoryBranchTruncation(t *testing.T) {
+	h := historyGraph(t, Directed())
+
+	if err := h.AddVertex(4); err != nil {
+		t.Fatal(err)
+	}
+	h.Checkpoint("first")
+	first := historyState(t, h)
+
+	if err := h.AddVertex(5); err != nil {
+		t.Fatal(err)
+	}
+	h.Checkpoint("second")
+
+	if err := h.AddVertex(6); err != nil {
+		t.Fatal(err)
+	}
+	h.Checkpoint("third")
+
+	if err := h.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Undo(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Пока ничего не записано, к отменённым точкам можно вернуться
+	if err := h.RevertTo("third"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.RevertTo("first"); err != nil {
+		t.Fatal(err)
+	}
+	if got := historyState(t, h); got != first {
+		t.Fatalf("после RevertTo(first):\n%s\nожидалось:\n%s", got, first)
+	}
+
+	if err := h.AddEdge(1, 4); err != nil {
+		t.Fatal(err)
+	}
+	if h.CanRedo() {
+		t.Fatal("после нового шага остался повтор")
+	}
+	if err := h.Redo(); !errors.Is(err, ErrorNothingToRedo) {
+		t.Fatalf("Redo вернул %v", err)
+	}
+
+	for _, name := range []string{"second", "third"} {
+		if err := h.RevertTo(name); !errors.Is(err, ErrorCheckpointNotFound) {
+			t.Fatalf("точка %s из отброшенной ветки: RevertTo вернул %v", name, err)
+		}
+	}
+
+	if err := h.RevertTo("first"); err != nil {
+		t.Fatal(err)
+	}
+	if got := historyState(t, h); got != first {
+		t.Fatalf("после RevertTo(first):\n%s\nожидалось:\n%s", got, first)
+	}
+	if _, err := h.Vertex(5); !errors.Is(err, ErrorVertextNotFound) {
+		t.Fatalf("вершина из отброшенной ветки вернулась: %v", err)
+	}
+}
+
+// Неудачный Batch не оставляет ни изменений, ни шага истории
+func TestHistoryFailedBatch(t *testing.T) {
+	h := historyGraph(t, Directed())
+	if err := h.AddVertex(4); err != nil {
+		t.Fatal(err)
+	}
+	want := historyState(t, h)
+
+	failed := errors.New("откат")
+	err := h.Batch(func(tx Graph[int, int]) error {
+		if err := tx.AddEdge(1, 4); err != nil {
+			return err
+		}
+		// Вложенный Batch пишет в тот же шаг
+		if err := tx.Batch(func(inner Graph[int, int]) error {
+			return inner.EditEdge(1, 2, EdgeWeight(0))
+		}); err != nil {
+			return err
+		}
+		if _, err := tx.RemoveVertexCascade(3); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("Batch вернул %v", err)
+	}
+
+	if got := historyState(t, h); got != want {
+		t.Fatalf("после неудачного Batch:\n%s\nожидалось:\n%s", got, want)
+	}
+
+	// Отменяется только AddVertex, неудачный Batch шагом не стал
+	if err := h.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if h.CanUndo() {
+		t.Fatal("неудачный Batch оставил шаг истории")
+	}
+}
+
+func TestHistoryCascadeUndo(t *testing.T) {
+	for _, kind := range historyKinds {
+		t.Run(kind.name, func(t *testing.T) {
+			h := historyGraph(t, kind.options...)
+			if err := h.AddEdge(3, 2, EdgeWeight(32)); err != nil && !errors.Is(err, ErrorEdgeExists) {
+				t.Fatal(err)
+			}
+			before := historyState(t, h)
+
+			removed, err := h.RemoveVertexCascade(3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(removed) == 0 {
+				t.Fatal("RemoveVertexCascade не удалил дуг")
+			}
+			after := historyState(t, h)
+
+			if err := h.Undo(); err != nil {
+				t.Fatal(err)
+			}
+			if got := historyState(t, h); got != before {
+				t.Fatalf("после отмены каскада:\n%s\nожидалось:\n%s", got, before)
+			}
+
+			if err := h.Redo(); err != nil {
+				t.Fatal(err)
+			}
+			if got := historyState(t, h); got != after {
+				t.Fatalf("после повтора каскада:\n%s\nожидалось:\n%s", got, after)
+			}
+		})
+	}
+}
+
+// EditEdge мультиграфа меняет все дуги пары, отмена возвращает каждой её свойства
+func TestHistoryMultigraphEditEdge(t *testing.T) {
+	g := New(IntHash, Directed(), Multigraph())
+	for i := 1; i <= 2; i++ {
+		if err := g.AddVertex(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := NewHistory(g)
+
+	ids := make([]uint64, 0, 3)
+	for _, weight := range []int{1, 2, 3} {
+		id, err := h.AddEdgeWithID(1, 2, EdgeWeight(weight), edgeAttribute("n", fmt.Sprint(weight)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	before := historyState(t, h)
+
+	if err := h.EditEdge(1, 2, EdgeWeight(9)); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.EditEdgeByID(ids[1], edgeAttribute("n", "by id")); err != nil {
+		t.Fatal(err)
+	}
+	after := historyState(t, h)
+
+	edges, err := h.EdgesBetween(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, edge := range edges {
+		if edge.Properties.Weight != 9 {
+			t.Fatalf("EditEdge не изменил дугу %d: %+v", edge.Properties.ID, edge.Properties)
+		}
+	}
+
+	if err := h.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := historyState(t, h); got != before {
+		t.Fatalf("после отмены:\n%s\nожидалось:\n%s", got, before)
+	}
+
+	for i, id := range ids {
+		edge, err := h.EdgeByID(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if edge.Properties.Weight != i+1 || edge.Properties.Attributes["n"] != fmt.Sprint(i+1) {
+			t.Fatalf("дуга %d после отмены %+v", id, edge.Properties)
+		}
+	}
+
+	if err := h.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := historyState(t, h); got != after {
+		t.Fatalf("после повтора:\n%s\nожидалось:\n%s", got, after)
+	}
+}
@@ -0,0 +1,246 @@
+package graph
+
+import "errors"
+
+// Хранилище с индексами по свойствам вершин.
+// Индекс объявляется один раз, сразу заполняется уже лежащими вершинами
+// и дальше сам обновляется при AddVertex, EditVertex и RemoveVertex.
+// Запросы без объявленного индекса возвращают ErrorIndexNotFound
+type VertexIndexStore[K comparable] interface {
+	// Объявляет индекс по атрибуту. Повторное объявление ничего не делает
+	CreateIndex(attribute string) error
+	// Объявляет индекс по весу вершины для запросов по диапазону.
+	// Индексируется FloatWeight, граф держит его равным Weight
+	CreateWeightIndex() error
+	// Вершины, у которых атрибут attribute равен value
+	VerticesByAttribute(attribute, value string) ([]K, error)
+	// Вершины с весом от min до max включительно
+	VerticesByWeight(min, max float64) ([]K, error)
+}
+
+// Объявляет индексы по атрибутам в хранилище графа.
+// Хранилище должно реализовывать VertexIndexStore, иначе ErrorIndexNotFound
+func CreateVertexIndex[K comparable, T any](g Graph[K, T], attributes ...string) error {
+	indexes, err := vertexIndexes(g)
+	if err != nil {
+		return err
+	}
+
+	for _, attribute := range attributes {
+		if err := indexes.CreateIndex(attribute); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Объявляет индекс по весу вершин в хранилище графа
+func CreateVertexWeightIndex[K comparable, T any](g Graph[K, T]) error {
+	indexes, err := vertexIndexes(g)
+	if err != nil {
+		return err
+	}
+
+	return indexes.CreateWeightIndex()
+}
+
+func vertexIndexes[K comparable, T any](g Graph[K, T]) (VertexIndexStore[K], error) {
+	if inner, ok := g.(storeGraph[K, T]); ok {
+		if indexes, ok := inner.storage().(VertexIndexStore[K]); ok {
+			return indexes, nil
+		}
+	}
+
+	return nil, ErrorIndexNotFound
+}
+
+// Условие на свойства вершины для FindVertices.
+// Условия на атрибут и вес используют индекс, если он объявлен.
+// Нулевое VertexCondition{} подходит любой вершине
+type VertexCondition struct {
+	match func(properties VertexProperties) bool
+
+	// Для поиска по индексу
+	attribute, value string
+	hasAttribute     bool
+
+	min, max  float64
+	hasWeight bool
+}
+
+// Атрибут attribute равен value
+func AttributeEquals(attribute, value string) VertexCondition {
+	return VertexCondition{
+		match: func(p VertexProperties) bool {
+			actual, ok := p.Attributes[attribute]
+			return ok && actual == value
+		},
+		attribute:    attribute,
+		value:        value,
+		hasAttribute: true,
+	}
+}
+
+// Вес вершины от min до max включительно. Сравнивается FloatWeight,
+// поэтому условие работает и для целых, и для дробных весов
+func WeightBetween(min, max float64) VertexCondition {
+	return VertexCondition{
+		match: func(p VertexProperties) bool {
+			return p.FloatWeight >= min && p.FloatWeight <= max
+		},
+		min:       min,
+		max:       max,
+		hasWeight: true,
+	}
+}
+
+// У вершины есть метка label
+func HasLabel(label string) VertexCondition {
+	return VertexMatches(func(p VertexProperties) bool {
+		return p.HasLabel(label)
+	})
+}
+
+// Произвольное условие на свойства. Индексом не ускоряется
+func VertexMatches(match func(properties VertexProperties) bool) VertexCondition {
+	return VertexCondition{match: match}
+}
+
+// Выполнено хотя бы одно из условий
+func AnyOf(conditions ...VertexCondition) VertexCondition {
+	return VertexMatches(func(p VertexProperties) bool {
+		for _, condition := range conditions {
+			if condition.matches(p) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// Условие не выполнено
+func Not(condition VertexCondition) VertexCondition {
+	return VertexMatches(func(p VertexProperties) bool {
+		return !condition.matches(p)
+	})
+}
+
+// Вершины, у которых атрибут attribute равен value
+func VerticesWhere[K comparable, T any](g Graph[K, T], attribute, value string) ([]K, error) {
+	return FindVertices(g, AttributeEquals(attribute, value))
+}
+
+// Вершины с весом от min до max включительно
+func VerticesWithWeight[K comparable, T any](g Graph[K, T], min, max float64) ([]K, error) {
+	return FindVertices(g, WeightBetween(min, max))
+}
+
+// Вершины, для которых выполнены все условия.
+// Кандидатов даёт первое условие, для которого в хранилище есть индекс,
+// остальные проверяются по свойствам. Без индексов проходятся все вершины.
+// Запрос читает согласованный вид хранилища. Порядок вершин не задан
+func FindVertices[K comparable, T any](g Graph[K, T], conditions ...VertexCondition) ([]K, error) {
+	inner, ok := g.(storeGraph[K, T])
+	if !ok || inner.storage() == nil {
+		return scanVertices(g, conditions)
+	}
+
+	var res []K
+
+	err := runView(inner.storage(), func(view Store[K, T]) error {
+		candidates, err := indexedCandidates(view, conditions)
+		if err != nil {
+			return err
+		}
+
+		if candidates == nil {
+			if candidates, err = view.ListVertices(); err != nil {
+				return err
+			}
+		}
+
+		res = make([]K, 0)
+		for _, hash := range candidates {
+			_, properties, err := view.Vertex(hash)
+			if err != nil {
+				return err
+			}
+
+			if matchVertex(properties, conditions) {
+				res = append(res, hash)
+			}
+		}
+
+		return nil
+	})
+
+	return res, err
+}
+
+// Вершины из первого индекса, подходящего под условия. nil, если такого нет
+func indexedCandidates[K comparable, T any](s Store[K, T], conditions []VertexCondition) ([]K, error) {
+	indexes, ok := s.(VertexIndexStore[K])
+	if !ok {
+		return nil, nil
+	}
+
+	for _, condition := range conditions {
+		var candidates []K
+		var err error
+
+		switch {
+		case condition.hasAttribute:
+			candidates, err = indexes.VerticesByAttribute(condition.attribute, condition.value)
+		case condition.hasWeight:
+			candidates, err = indexes.VerticesByWeight(condition.min, condition.max)
+		default:
+			continue
+		}
+
+		if errors.Is(err, ErrorIndexNotFound) {
+			continue
+		}
+
+		return candidates, err
+	}
+
+	return nil, nil
+}
+
+// Полный проход для графа без доступа к хранилищу
+func scanVertices[K comparable, T any](g Graph[K, T], conditions []VertexCondition) ([]K, error) {
+	adjacency, err := g.AdjacencyMap()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]K, 0)
+	for hash := range adjacency {
+		_, properties, err := g.VertexWithProperties(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		if matchVertex(properties, conditions) {
+			res = append(res, hash)
+		}
+	}
+
+	return res, nil
+}
+
+func matchVertex(properties VertexProperties, conditions []VertexCondition) bool {
+	for _, condition := range conditions {
+		if !condition.matches(properties) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c VertexCondition) matches(properties VertexProperties) bool {
+	return c.match == nil || c.match(properties)
+}
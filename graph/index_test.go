@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCreateIndexOnSnapshot(t *testing.T) {
+	stores := map[string]Store[int, int]{
+		"memory":  NewMemoryStore[int, int](),
+		"sharded": NewShardedStore[int, int](4),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithStore(IntHash, store, Directed())
+			if err := g.AddVertex(1, vertexAttribute("x", "1")); err != nil {
+				t.Fatal(err)
+			}
+
+			snapshot, err := g.Snapshot()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer snapshot.Release()
+
+			// Карты снимка общие с живым хранилищем, индекс в них писать нельзя
+			if err := CreateVertexIndex[int, int](snapshot, "x"); !errors.Is(err, ErrorGraphReadOnly) {
+				t.Fatalf("CreateVertexIndex на снимке вернул %v", err)
+			}
+			if err := CreateVertexWeightIndex[int, int](snapshot); !errors.Is(err, ErrorGraphReadOnly) {
+				t.Fatalf("CreateVertexWeightIndex на снимке вернул %v", err)
+			}
+
+			// Живое хранилище индекс так и не получило
+			indexes, err := vertexIndexes(g)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := indexes.VerticesByAttribute("x", "1"); !errors.Is(err, ErrorIndexNotFound) {
+				t.Fatalf("у живого хранилища появился индекс: %v", err)
+			}
+
+			// Запросы по снимку работают и без индекса
+			found, err := VerticesWhere[int, int](snapshot, "x", "1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(found, []int{1}) {
+				t.Fatalf("VerticesWhere на снимке = %v", found)
+			}
+		})
+	}
+}
+
+func TestZeroVertexCondition(t *testing.T) {
+	g := New(IntHash)
+	for i := 0; i < 3; i++ {
+		if err := g.AddVertex(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := FindVertices(g, VertexCondition{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(all)
+	if !slices.Equal(all, []int{0, 1, 2}) {
+		t.Fatalf("FindVertices(VertexCondition{}) = %v", all)
+	}
+
+	none, err := FindVertices(g, Not(VertexCondition{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(none) != 0 {
+		t.Fatalf("FindVertices(Not(VertexCondition{})) = %v", none)
+	}
+}
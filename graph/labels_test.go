@@ -0,0 +1,223 @@
+package graph
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestVertexLabels(t *testing.T) {
+	g := New(IntHash, Directed())
+
+	if err := g.AddVertex(1, VertexLabels("Repo", "Person"), VertexLabels("Person", "Admin")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, properties, err := g.VertexWithProperties(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Без повторов и по возрастанию
+	if want := []string{"Admin", "Person", "Repo"}; !slices.Equal(properties.Labels, want) {
+		t.Fatalf("метки %v, ожидалось %v", properties.Labels, want)
+	}
+	if !properties.HasLabel("Person") || properties.HasLabel("Team") {
+		t.Fatalf("HasLabel по меткам %v", properties.Labels)
+	}
+
+	if err := g.EditVertex(1, DropVertexLabels("Repo", "Team"), VertexLabels("Bot")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, edited, err := g.VertexWithProperties(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Admin", "Bot", "Person"}; !slices.Equal(edited.Labels, want) {
+		t.Fatalf("метки после EditVertex %v, ожидалось %v", edited.Labels, want)
+	}
+
+	// Опции не правят срез, прочитанный раньше
+	if want := []string{"Admin", "Person", "Repo"}; !slices.Equal(properties.Labels, want) {
+		t.Fatalf("прочитанные раньше метки изменились: %v", properties.Labels)
+	}
+}
+
+func TestHasLabelCondition(t *testing.T) {
+	g := New(IntHash, Directed())
+
+	for i := 1; i <= 6; i++ {
+		var options []func(*VertexProperties)
+		if i%2 == 0 {
+			options = append(options, VertexLabels("Even"))
+		}
+		if i%3 == 0 {
+			options = append(options, VertexLabels("Triple"))
+		}
+
+		if err := g.AddVertex(i, options...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		conditions []VertexCondition
+		want       []int
+	}{
+		{"Even", []VertexCondition{HasLabel("Even")}, []int{2, 4, 6}},
+		{"Even и Triple", []VertexCondition{HasLabel("Even"), HasLabel("Triple")}, []int{6}},
+		{"Even или Triple", []VertexCondition{AnyOf(HasLabel("Even"), HasLabel("Triple"))}, []int{2, 3, 4, 6}},
+		{"без меток", []VertexCondition{Not(AnyOf(HasLabel("Even"), HasLabel("Triple")))}, []int{1, 5}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := FindVertices(g, test.conditions...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sortedCopy(got); !slices.Equal(got, test.want) {
+				t.Fatalf("FindVertices = %v, ожидалось %v", got, test.want)
+			}
+		})
+	}
+}
+
+// Тип дуги сохраняется при правке, копировании и заморозке графа
+func TestEdgeType(t *testing.T) {
+	g := New(IntHash, Directed(), Multigraph())
+	for i := 1; i <= 2; i++ {
+		if err := g.AddVertex(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	owns, err := g.AddEdgeWithID(1, 2, EdgeType("OWNS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	follows, err := g.AddEdgeWithID(1, 2, EdgeType("FOLLOWS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.EditEdgeByID(follows, EdgeWeight(3)); err != nil {
+		t.Fatal(err)
+	}
+
+	clone, err := g.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	frozen, err := Freeze(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, graph := range map[string]Graph[int, int]{"graph": g, "clone": clone, "frozen": frozen} {
+		for id, want := range map[uint64]string{owns: "OWNS", follows: "FOLLOWS"} {
+			edge, err := graph.EdgeByID(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if edge.Properties.Type != want {
+				t.Fatalf("%s: у дуги %d тип %q, ожидалось %q", name, id, edge.Properties.Type, want)
+			}
+		}
+	}
+}
+
+func TestFollowEdgeTypes(t *testing.T) {
+	// 1 -OWNS-> 2 -FOLLOWS-> 3 -OWNS-> 4, и дуга без типа 1 -> 5
+	edges := []struct {
+		source, target int
+		edgeType       string
+	}{
+		{1, 2, "OWNS"},
+		{2, 3, "FOLLOWS"},
+		{3, 4, "OWNS"},
+		{1, 5, ""},
+	}
+
+	tests := []struct {
+		name    string
+		options []func(*TraversalOptions)
+		want    []int
+	}{
+		{"все дуги", nil, []int{1, 2, 3, 4, 5}},
+		{"OWNS", []func(*TraversalOptions){FollowEdgeTypes("OWNS")}, []int{1, 2}},
+		{"OWNS и FOLLOWS", []func(*TraversalOptions){FollowEdgeTypes("OWNS"), FollowEdgeTypes("FOLLOWS")}, []int{1, 2, 3, 4}},
+		{"без типа", []func(*TraversalOptions){FollowEdgeTypes("")}, []int{1, 5}},
+		{"неизвестный тип", []func(*TraversalOptions){FollowEdgeTypes("LIKES")}, []int{1}},
+	}
+
+	for _, kind := range []struct {
+		name    string
+		options []func(*Traits)
+	}{
+		{"directed", []func(*Traits){Directed()}},
+		{"undirected", nil},
+	} {
+		g := New(IntHash, kind.options...)
+		for i := 1; i <= 5; i++ {
+			if err := g.AddVertex(i); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, edge := range edges {
+			if err := g.AddEdge(edge.source, edge.target, EdgeType(edge.edgeType)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, test := range tests {
+			t.Run(kind.name+"/"+test.name, func(t *testing.T) {
+				traversals := map[string]func(visit func(int) bool) error{
+					"BFS": func(visit func(int) bool) error { return BFS(g, 1, visit, test.options...) },
+					"DFS": func(visit func(int) bool) error { return DFS(g, 1, visit, test.options...) },
+				}
+
+				for name, traverse := range traversals {
+					var visited []int
+					err := traverse(func(v int) bool {
+						visited = append(visited, v)
+						return false
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if got := sortedCopy(visited); !slices.Equal(got, test.want) {
+						t.Fatalf("%s прошёл %v, ожидалось %v", name, got, test.want)
+					}
+				}
+			})
+		}
+	}
+
+	// У ненаправленного графа фильтр работает в обе стороны
+	g := New[int, int](IntHash)
+	for i := 1; i <= 3; i++ {
+		if err := g.AddVertex(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := g.AddEdge(2, 1, EdgeType("OWNS")); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddEdge(3, 2, EdgeType("FOLLOWS")); err != nil {
+		t.Fatal(err)
+	}
+
+	var visited []int
+	err := BFS(g, 1, func(v int) bool {
+		visited = append(visited, v)
+		return false
+	}, FollowEdgeTypes("OWNS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(visited, []int{1, 2}) {
+		t.Fatalf("BFS против направления добавления прошёл %v", visited)
+	}
+}
@@ -0,0 +1,85 @@
+package graph
+
+import "sync/atomic"
+
+// Хранилище, которое держит несколько дуг между одной парой вершин.
+// Дуги пары различаются по Properties.ID. Первая дуга пары видна и через обычные
+// методы Store, а степени, ListEdges, OutEdges и InEdges учитывают все дуги.
+// RemoveEdge удаляет все дуги пары
+type MultiEdgeStore[K comparable, T any] interface {
+	// Добавляет ещё одну дугу source -> target
+	AddParallelEdge(source, target K, edge Edge[K]) error
+	// Заменяет дугу source -> target с тем же ID
+	EditParallelEdge(source, target K, edge Edge[K]) error
+	// Удаляет дугу source -> target с данным ID
+	RemoveParallelEdge(source, target K, id uint64) error
+	// Все дуги source -> target
+	EdgesBetween(source, target K) ([]Edge[K], error)
+	// Дуга по ID. У ненаправленного графа одна из двух половин
+	EdgeByID(id uint64) (Edge[K], error)
+}
+
+func multiEdges[K comparable, T any](s Store[K, T]) (MultiEdgeStore[K, T], error) {
+	if multi, ok := s.(MultiEdgeStore[K, T]); ok {
+		return multi, nil
+	}
+
+	return nil, ErrorMultigraphNotSupported
+}
+
+// Счётчик ID дуг мультиграфа. Общий для графа и его Batch
+type edgeCounter struct {
+	last atomic.Uint64
+}
+
+// Продолжает счёт после дуг, уже лежащих в хранилище
+func newEdgeCounter[K comparable, T any](store Store[K, T], traits *Traits) *edgeCounter {
+	counter := &edgeCounter{}
+	if !traits.IsMultigraph {
+		return counter
+	}
+
+	// Ошибку увидит первый же вызов графа
+	edges, _ := store.ListEdges()
+	for _, edge := range edges {
+		counter.seen(edge.Properties.ID)
+	}
+
+	return counter
+}
+
+func (c *edgeCounter) next() uint64 {
+	return c.last.Add(1)
+}
+
+// Запоминает ID, выбранный снаружи, чтобы next его не выдал
+func (c *edgeCounter) seen(id uint64) {
+	for {
+		last := c.last.Load()
+		if id <= last || c.last.CompareAndSwap(last, id) {
+			return
+		}
+	}
+}
+
+// Выдаёт дуге новый ID или проверяет, что ID, выбранный снаружи, ещё свободен.
+// Проверка смотрит все дуги, поэтому хранилище должно быть заблокировано целиком
+func takeEdgeID[K comparable, T any](store Store[K, T], ids *edgeCounter, properties *EdgeProperties) error {
+	multi, err := multiEdges(store)
+	if err != nil {
+		return err
+	}
+
+	if properties.ID == 0 {
+		properties.ID = ids.next()
+		return nil
+	}
+
+	if _, err := multi.EdgeByID(properties.ID); err == nil {
+		return ErrorEdgeExists
+	}
+
+	ids.seen(properties.ID)
+
+	return nil
+}
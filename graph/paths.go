@@ -0,0 +1,225 @@
+package graph
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// Взвешенные алгоритмы. Вес дуги берётся из WeightOptions, по умолчанию Properties.Weight
+// или Properties.FloatWeight у графа FloatWeighted
+
+// Кратчайший путь от source до target алгоритмом Дейкстры.
+// Возвращает вершины пути вместе с концами и его длину.
+// Из параллельных дуг выбирается самая лёгкая. Отрицательный вес это ErrorNegativeWeight
+func ShortestPath[K comparable, T any](g Graph[K, T], source, target K, options ...func(*WeightOptions[K])) ([]K, float64, error) {
+	distances, parents, err := dijkstra(g, source, &target, weightOptions(g.Traits(), options))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	distance, ok := distances[target]
+	if !ok {
+		return nil, 0, ErrorTargetNotReachable
+	}
+
+	path := []K{target}
+	for current := target; current != source; {
+		current = parents[current]
+		path = append(path, current)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, distance, nil
+}
+
+// Длины кратчайших путей от source до всех достижимых вершин
+func ShortestDistances[K comparable, T any](g Graph[K, T], source K, options ...func(*WeightOptions[K])) (map[K]float64, error) {
+	distances, _, err := dijkstra(g, source, nil, weightOptions(g.Traits(), options))
+	return distances, err
+}
+
+// Дейкстра с остановкой на target, если он задан.
+// Возвращает окончательные расстояния и родителей вершин в дереве путей
+func dijkstra[K comparable, T any](g Graph[K, T], source K, target *K, opts WeightOptions[K]) (map[K]float64, map[K]K, error) {
+	if _, err := g.Vertex(source); err != nil {
+		return nil, nil, err
+	}
+
+	best := map[K]float64{source: 0}
+	parents := make(map[K]K)
+	done := make(map[K]float64)
+
+	queue := &distanceQueue[K]{}
+	heap.Push(queue, distanceItem[K]{vertex: source})
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(distanceItem[K])
+
+		// Вершина уже закрыта более коротким путём
+		if _, ok := done[item.vertex]; ok {
+			continue
+		}
+		done[item.vertex] = item.distance
+
+		if target != nil && item.vertex == *target {
+			break
+		}
+
+		edges, err := g.OutEdges(item.vertex)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, edge := range edges {
+			weight, err := opts.Weight(edge)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if weight < 0 {
+				return nil, nil, ErrorNegativeWeight
+			}
+
+			distance := item.distance + weight
+			if current, ok := best[edge.Target]; ok && current <= distance {
+				continue
+			}
+
+			best[edge.Target] = distance
+			parents[edge.Target] = item.vertex
+			heap.Push(queue, distanceItem[K]{vertex: edge.Target, distance: distance})
+		}
+	}
+
+	return done, parents, nil
+}
+
+type distanceItem[K comparable] struct {
+	vertex   K
+	distance float64
+}
+
+// Очередь с приоритетом для container/heap, ближайшая вершина сверху
+type distanceQueue[K comparable] []distanceItem[K]
+
+func (q distanceQueue[K]) Len() int {
+	return len(q)
+}
+
+func (q distanceQueue[K]) Less(i, j int) bool {
+	return q[i].distance < q[j].distance
+}
+
+func (q distanceQueue[K]) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *distanceQueue[K]) Push(x any) {
+	*q = append(*q, x.(distanceItem[K]))
+}
+
+func (q *distanceQueue[K]) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return item
+}
+
+// Минимальный остовный лес ненаправленного графа алгоритмом Краскала.
+// Результат это новый граф того же вида со всеми вершинами и дугами леса
+func MinimumSpanningTree[K comparable, T any](g Graph[K, T], options ...func(*WeightOptions[K])) (Graph[K, T], error) {
+	if g.Traits().IsDirected {
+		return nil, ErrorNotUndirected
+	}
+
+	opts := weightOptions(g.Traits(), options)
+
+	edges, err := g.Edges()
+	if err != nil {
+		return nil, err
+	}
+
+	weights := make([]float64, len(edges))
+	for i, edge := range edges {
+		if weights[i], err = opts.Weight(edge); err != nil {
+			return nil, err
+		}
+	}
+
+	order := make([]int, len(edges))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return weights[order[i]] < weights[order[j]]
+	})
+
+	tree, err := newDerived(g)
+	if err != nil {
+		return nil, err
+	}
+
+	components := newDisjointSet[K]()
+
+	for _, i := range order {
+		edge := edges[i]
+		if !components.union(edge.Source, edge.Target) {
+			continue
+		}
+
+		if err := tree.AddEdge(copyEdge(edge)); err != nil {
+			return nil, err
+		}
+	}
+
+	return tree, nil
+}
+
+// Система непересекающихся множеств со сжатием путей
+type disjointSet[K comparable] struct {
+	parents map[K]K
+	ranks   map[K]int
+}
+
+func newDisjointSet[K comparable]() *disjointSet[K] {
+	return &disjointSet[K]{
+		parents: make(map[K]K),
+		ranks:   make(map[K]int),
+	}
+}
+
+func (d *disjointSet[K]) find(x K) K {
+	parent, ok := d.parents[x]
+	if !ok || parent == x {
+		return x
+	}
+
+	root := d.find(parent)
+	d.parents[x] = root
+
+	return root
+}
+
+// Объединяет множества a и b. false, если они уже были одним
+func (d *disjointSet[K]) union(a, b K) bool {
+	a, b = d.find(a), d.find(b)
+	if a == b {
+		return false
+	}
+
+	if d.ranks[a] < d.ranks[b] {
+		a, b = b, a
+	}
+
+	d.parents[b] = a
+	if d.ranks[a] == d.ranks[b] {
+		d.ranks[a]++
+	}
+
+	return true
+}
@@ -0,0 +1,189 @@
+package graph
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// Направленный граф с весом и атрибутом latency у каждой дуги:
+//
+//	1 -> 2 (7), 1 -> 3 (9), 1 -> 6 (14), 2 -> 3 (10), 2 -> 4 (15),
+//	3 -> 4 (11), 3 -> 6 (2), 4 -> 5 (6), 6 -> 5 (9), вершина 7 без дуг.
+//
+// По latency путь 1 -> 2 -> 4 -> 5 короче остальных
+func pathsGraph(t *testing.T) Graph[int, int] {
+	t.Helper()
+
+	g := New(IntHash, Directed(), Weighted())
+	for i := 1; i <= 7; i++ {
+		if err := g.AddVertex(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	edges := [][4]int{
+		{1, 2, 7, 1}, {1, 3, 9, 100}, {1, 6, 14, 100}, {2, 3, 10, 1}, {2, 4, 15, 1},
+		{3, 4, 11, 100}, {3, 6, 2, 100}, {4, 5, 6, 1}, {6, 5, 9, 1},
+	}
+	for _, e := range edges {
+		if err := g.AddEdge(e[0], e[1], EdgeWeight(e[2]), edgeAttribute("latency", strconv.Itoa(e[3]))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return g
+}
+
+func TestShortestPath(t *testing.T) {
+	g := pathsGraph(t)
+
+	tests := []struct {
+		name     string
+		target   int
+		options  []func(*WeightOptions[int])
+		path     []int
+		distance float64
+		err      error
+	}{
+		{"к себе", 1, nil, []int{1}, 0, nil},
+		{"через промежуточные", 5, nil, []int{1, 3, 6, 5}, 20, nil},
+		{"соседняя", 2, nil, []int{1, 2}, 7, nil},
+		{"по атрибуту", 5, []func(*WeightOptions[int]){WeightBy(WeightFromAttribute[int]("latency"))}, []int{1, 2, 4, 5}, 3, nil},
+		{"недостижимая", 7, nil, nil, 0, ErrorTargetNotReachable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path, distance, err := ShortestPath(g, 1, test.target, test.options...)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("ошибка %v, ожидалась %v", err, test.err)
+			}
+			if !slices.Equal(path, test.path) || distance != test.distance {
+				t.Fatalf("ShortestPath = %v, %v, ожидалось %v, %v", path, distance, test.path, test.distance)
+			}
+		})
+	}
+}
+
+func TestShortestPathErrors(t *testing.T) {
+	g := pathsGraph(t)
+
+	if _, _, err := ShortestPath(g, 100, 1); !errors.Is(err, ErrorVertextNotFound) {
+		t.Fatalf("нет начальной вершины, ошибка %v", err)
+	}
+
+	if err := g.EditEdge(6, 5, EdgeWeight(-1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ShortestPath(g, 1, 5); !errors.Is(err, ErrorNegativeWeight) {
+		t.Fatalf("отрицательный вес, ошибка %v", err)
+	}
+
+	// Ошибка функции веса прерывает поиск
+	if _, err := ShortestDistances(g, 1, WeightBy(WeightFromAttribute[int]("cost"))); !errors.Is(err, ErrorWeightMissing) {
+		t.Fatalf("нет атрибута, ошибка %v", err)
+	}
+}
+
+func TestShortestDistances(t *testing.T) {
+	distances, err := ShortestDistances(pathsGraph(t), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int]float64{1: 0, 2: 7, 3: 9, 4: 20, 5: 20, 6: 11}
+	if !maps.Equal(distances, want) {
+		t.Fatalf("ShortestDistances = %v, ожидалось %v", distances, want)
+	}
+}
+
+// Из параллельных дуг берётся самая лёгкая
+func TestShortestPathMultigraph(t *testing.T) {
+	g := New(IntHash, Directed(), Multigraph(), Weighted())
+	_ = g.AddVertex(1)
+	_ = g.AddVertex(2)
+	_ = g.AddEdge(1, 2, EdgeWeight(5))
+	_ = g.AddEdge(1, 2, EdgeWeight(2))
+	_ = g.AddEdge(1, 2, EdgeWeight(8))
+
+	_, distance, err := ShortestPath(g, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if distance != 2 {
+		t.Fatalf("расстояние %v, ожидалось 2", distance)
+	}
+}
+
+func TestMinimumSpanningTree(t *testing.T) {
+	// Две компоненты: квадрат с диагональю 1-2-3-4 и ребро 5-6
+	g := New(IntHash, Weighted())
+	for i := 1; i <= 6; i++ {
+		_ = g.AddVertex(i)
+	}
+
+	edges := [][3]int{{1, 2, 2}, {2, 3, 4}, {3, 4, 1}, {4, 1, 3}, {1, 3, 5}, {5, 6, 7}}
+	for _, e := range edges {
+		// Атрибут с обратным порядком весов для проверки WeightBy
+		if err := g.AddEdge(e[0], e[1], EdgeWeight(e[2]), edgeAttribute("reverse", strconv.Itoa(10-e[2]))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		options []func(*WeightOptions[int])
+		weight  int
+		edges   [][2]int
+	}{
+		{"по весу", nil, 13, [][2]int{{1, 2}, {1, 4}, {3, 4}, {5, 6}}},
+		{"по атрибуту", []func(*WeightOptions[int]){WeightBy(WeightFromAttribute[int]("reverse"))}, 19, [][2]int{{1, 3}, {2, 3}, {1, 4}, {5, 6}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree, err := MinimumSpanningTree(g, test.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if order, _ := tree.Order(); order != 6 {
+				t.Fatalf("в лесу %d вершин, ожидалось 6", order)
+			}
+
+			treeEdges, err := tree.Edges()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			weight := 0
+			for _, edge := range treeEdges {
+				weight += edge.Properties.Weight
+			}
+			if weight != test.weight || len(treeEdges) != len(test.edges) {
+				t.Fatalf("вес леса %d из %d рёбер, ожидалось %d из %d", weight, len(treeEdges), test.weight, len(test.edges))
+			}
+
+			for _, edge := range test.edges {
+				if _, err := tree.Edge(edge[0], edge[1]); err != nil {
+					t.Fatalf("нет ребра %v: %v", edge, err)
+				}
+			}
+		})
+	}
+}
+
+func TestMinimumSpanningTreeDirected(t *testing.T) {
+	if _, err := MinimumSpanningTree(pathsGraph(t)); !errors.Is(err, ErrorNotUndirected) {
+		t.Fatalf("направленный граф, ошибка %v", err)
+	}
+}
+
+func edgeAttribute(key, value string) func(*EdgeProperties) {
+	return func(p *EdgeProperties) {
+		p.Attributes[key] = value
+	}
+}
@@ -0,0 +1,395 @@
+package graph
+
+// Неизменяемый граф с общими между версиями данными.
+// Методы With* возвращают новую версию графа, а старая остаётся как была.
+// Новая версия делит со старой почти все данные, поэтому изменение стоит O(log n)
+// памяти и времени, а Clone ничего не копирует.
+// Удобен для отмены действий и для сравнения вариантов «что если».
+//
+// Реализует Graph для чтения, поэтому на нём работают все алгоритмы пакета.
+// Изменяющие методы Graph возвращают ErrorGraphReadOnly.
+// Версии не меняются, поэтому их можно читать из любых горутин без блокировок,
+// а Observe и Subscribe на версии никогда не срабатывают
+type Persistent[K comparable, T any] struct {
+	Graph[K, T]
+}
+
+// Пустой неизменяемый граф
+func NewPersistent[K comparable, T any](hash Hash[K, T], options ...func(*Traits)) *Persistent[K, T] {
+	return &Persistent[K, T]{
+		Graph: NewWithStore[K, T](hash, newHamtStore[K, T](), options...),
+	}
+}
+
+// Неизменяемая копия графа g
+func NewPersistentFrom[K comparable, T any](g Graph[K, T]) (*Persistent[K, T], error) {
+	hash := hashOf(g)
+	if hash == nil {
+		return nil, ErrorHashUnknown
+	}
+
+	copyTraits := func(t *Traits) {
+		*t = *g.Traits()
+	}
+
+	return NewPersistent(hash, copyTraits).WithBatch(func(tx Graph[K, T]) error {
+		if err := tx.AddVerticesFrom(g); err != nil {
+			return err
+		}
+
+		return tx.AddEdgesFrom(g)
+	})
+}
+
+// Новая версия графа с добавленной вершиной
+func (p *Persistent[K, T]) WithVertex(value T, options ...func(*VertexProperties)) (*Persistent[K, T], error) {
+	return p.WithBatch(func(tx Graph[K, T]) error {
+		return tx.AddVertex(value, options...)
+	})
+}
+
+// Новая версия графа без вершины hash. Как и RemoveVertex, не удаляет вершину с дугами
+func (p *Persistent[K, T]) WithoutVertex(hash K) (*Persistent[K, T], error) {
+	return p.WithBatch(func(tx Graph[K, T]) error {
+		return tx.RemoveVertex(hash)
+	})
+}
+
+// Новая версия графа без вершины hash и всех её дуг
+func (p *Persistent[K, T]) WithoutVertexCascade(hash K) (*Persistent[K, T], []Edge[K], error) {
+	var removed []Edge[K]
+
+	next, err := p.WithBatch(func(tx Graph[K, T]) error {
+		var err error
+		removed, err = tx.RemoveVertexCascade(hash)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return next, removed, nil
+}
+
+// Новая версия графа с изменёнными свойствами вершины
+func (p *Persistent[K, T]) WithEditedVertex(hash K, options ...func(*VertexProperties)) (*Persistent[K, T], error) {
+	return p.WithBatch(func(tx Graph[K, T]) error {
+		return tx.EditVertex(hash, options...)
+	})
+}
+
+// Новая версия графа с добавленной дугой
+func (p *Persistent[K, T]) WithEdge(source, target K, options ...func(*EdgeProperties)) (*Persistent[K, T], error) {
+	return p.WithBatch(func(tx Graph[K, T]) error {
+		return tx.AddEdge(source, target, options...)
+	})
+}
+
+// Новая версия графа с изменёнными свойствами дуги
+func (p *Persistent[K, T]) WithEditedEdge(source, target K, options ...func(*EdgeProperties)) (*Persistent[K, T], error) {
+	return p.WithBatch(func(tx Graph[K, T]) error {
+		return tx.EditEdge(source, target, options...)
+	})
+}
+
+// Новая версия графа без дуги
+func (p *Persistent[K, T]) WithoutEdge(source, target K) (*Persistent[K, T], error) {
+	return p.WithBatch(func(tx Graph[K, T]) error {
+		return tx.RemoveEdge(source, target)
+	})
+}
+
+// Новая версия графа после всех изменений fn.
+// tx это изменяемый граф поверх копии версии, после ошибки fn он просто выбрасывается
+func (p *Persistent[K, T]) WithBatch(fn func(tx Graph[K, T]) error) (*Persistent[K, T], error) {
+	inner := p.Graph.(storeGraph[K, T])
+	store := *inner.storage().(*hamtStore[K, T])
+
+	// Корень может смениться, поэтому у версии свои свойства
+	traits := *p.Traits()
+	copyTraits := func(t *Traits) {
+		*t = traits
+	}
+
+	tx := NewWithStore[K, T](inner.hashFunc(), &store, copyTraits)
+	if err := fn(tx); err != nil {
+		return nil, err
+	}
+
+	return &Persistent[K, T]{Graph: tx}, nil
+}
+
+func (p *Persistent[K, T]) AddVertex(_ T, _ ...func(*VertexProperties)) error {
+	return ErrorGraphReadOnly
+}
+
+func (p *Persistent[K, T]) RemoveVertex(_ K) error {
+	return ErrorGraphReadOnly
+}
+
+func (p *Persistent[K, T]) RemoveVertexCascade(_ K) ([]Edge[K], error) {
+	return nil, ErrorGraphReadOnly
+}
+
+func (p *Persistent[K, T]) EditVertex(_ K, _ ...func(*VertexProperties)) error {
+	return ErrorGraphReadOnly
+}
+
+func (p *Persistent[K, T]) AddEdge(_, _ K, _ ...func(*EdgeProperties)) error {
+	return ErrorGraphReadOnly
+}
+
+func (p *Persistent[K, T]) AddEdgeWithID(_, _ K, _ ...func(*EdgeProperties)) (uint64, error) {
+	return 0, ErrorGraphReadOnly
+}
+
+func (p *Persistent[K, T]) EditEdgeByID(_ uint64, _ ...func(properties *EdgeProperties)) error {
+	return ErrorGraphReadOnly
+}
+
+func (p *Persistent[K, T]) RemoveEdgeByID(_ uint64) error {
+	return ErrorGraphReadOnly
+}
+
+func (p *Persistent[K, T]) AddVerticesFrom(_ Graph[K, T]) error {
+	return ErrorGraphReadOnly
+}
+
+func (p *Persistent[K, T]) AddEdgesFrom(_ Graph[K, T]) error {
+	return ErrorGraphReadOnly
+}
+
+func (p *Persistent[K, T]) EditEdge(_, _ K, _ ...func(properties *EdgeProperties)) error {
+	return ErrorGraphReadOnly
+}
+
+func (p *Persistent[K, T]) RemoveEdge(_, _ K) error {
+	return ErrorGraphReadOnly
+}
+
+func (p *Persistent[K, T]) Batch(_ func(tx Graph[K, T]) error) error {
+	return ErrorGraphReadOnly
+}
+
+// Версия неизменяемая, поэтому копия не нужна
+func (p *Persistent[K, T]) Clone() (Graph[K, T], error) {
+	return p, nil
+}
+
+// Версия и так не меняется, отпускать нечего
+func (p *Persistent[K, T]) Snapshot() (*Snapshot[K, T], error) {
+	return newSnapshot[K, T](p, func() {}), nil
+}
+
+func (p *Persistent[K, T]) hashFunc() Hash[K, T] {
+	return hashOf(p.Graph)
+}
+
+// NewLike на версии создаёт обычный изменяемый граф в памяти
+func (p *Persistent[K, T]) storage() Store[K, T] {
+	return nil
+}
+
+// Хранилище поверх неизменяемых карт. Сама структура меняется при записи,
+// но карты внутри общие с другими копиями структуры, поэтому копия структуры
+// это независимая версия хранилища. Блокировок нет: каждую копию пишет только один владелец
+type hamtStore[K comparable, T any] struct {
+	vertices hamt[K, hamtVertex[T]]
+	outEdges hamt[K, hamt[K, Edge[K]]] // source -> target
+	inEdges  hamt[K, hamt[K, Edge[K]]] // target -> source
+}
+
+type hamtVertex[T any] struct {
+	value      T
+	properties VertexProperties
+}
+
+func newHamtStore[K comparable, T any]() *hamtStore[K, T] {
+	return &hamtStore[K, T]{
+		vertices: newHamt[K, hamtVertex[T]](keyHash[K]),
+		outEdges: newHamt[K, hamt[K, Edge[K]]](keyHash[K]),
+		inEdges:  newHamt[K, hamt[K, Edge[K]]](keyHash[K]),
+	}
+}
+
+// Дуги одной вершины. Пустая карта, если дуг нет
+func (s *hamtStore[K, T]) edges(m hamt[K, hamt[K, Edge[K]]], hash K) hamt[K, Edge[K]] {
+	edges, ok := m.get(hash)
+	if !ok {
+		return newHamt[K, Edge[K]](keyHash[K])
+	}
+
+	return edges
+}
+
+func (s *hamtStore[K, T]) AddVertex(hash K, value T, properties VertexProperties) error {
+	if _, ok := s.vertices.get(hash); ok {
+		return ErrorVertexExists
+	}
+
+	s.vertices = s.vertices.set(hash, hamtVertex[T]{value: value, properties: properties})
+
+	return nil
+}
+
+func (s *hamtStore[K, T]) Vertex(hash K) (T, VertexProperties, error) {
+	vertex, ok := s.vertices.get(hash)
+	if !ok {
+		var empty T
+		return empty, VertexProperties{}, ErrorVertextNotFound
+	}
+
+	return vertex.value, vertex.properties, nil
+}
+
+func (s *hamtStore[K, T]) RemoveVertex(hash K) error {
+	if _, ok := s.vertices.get(hash); !ok {
+		return ErrorVertextNotFound
+	}
+
+	if s.edges(s.outEdges, hash).len() > 0 || s.edges(s.inEdges, hash).len() > 0 {
+		return ErrorVertexHashEdges
+	}
+
+	s.vertices = s.vertices.delete(hash)
+	s.outEdges = s.outEdges.delete(hash)
+	s.inEdges = s.inEdges.delete(hash)
+
+	return nil
+}
+
+func (s *hamtStore[K, T]) EditVertex(hash K, properties VertexProperties) error {
+	vertex, ok := s.vertices.get(hash)
+	if !ok {
+		return ErrorVertextNotFound
+	}
+
+	vertex.properties = properties
+	s.vertices = s.vertices.set(hash, vertex)
+
+	return nil
+}
+
+func (s *hamtStore[K, T]) ListVertices() ([]K, error) {
+	res := make([]K, 0, s.vertices.len())
+	s.vertices.each(func(hash K, _ hamtVertex[T]) bool {
+		res = append(res, hash)
+		return true
+	})
+
+	return res, nil
+}
+
+func (s *hamtStore[K, T]) VertexCount() (int, error) {
+	return s.vertices.len(), nil
+}
+
+func (s *hamtStore[K, T]) AddEdge(source, target K, edge Edge[K]) error {
+	s.outEdges = s.outEdges.set(source, s.edges(s.outEdges, source).set(target, edge))
+	s.inEdges = s.inEdges.set(target, s.edges(s.inEdges, target).set(source, edge))
+
+	return nil
+}
+
+func (s *hamtStore[K, T]) EditEdge(source, target K, edge Edge[K]) error {
+	if _, ok := s.edges(s.outEdges, source).get(target); !ok {
+		return ErrorEdgeNotFound
+	}
+
+	return s.AddEdge(source, target, edge)
+}
+
+func (s *hamtStore[K, T]) RemoveEdge(source, target K) error {
+	if _, ok := s.edges(s.outEdges, source).get(target); !ok {
+		return nil
+	}
+
+	s.outEdges = s.outEdges.set(source, s.edges(s.outEdges, source).delete(target))
+	s.inEdges = s.inEdges.set(target, s.edges(s.inEdges, target).delete(source))
+
+	return nil
+}
+
+func (s *hamtStore[K, T]) Edge(source, target K) (Edge[K], error) {
+	edge, ok := s.edges(s.outEdges, source).get(target)
+	if !ok {
+		return Edge[K]{}, ErrorEdgeNotFound
+	}
+
+	return edge, nil
+}
+
+func (s *hamtStore[K, T]) ListEdges() ([]Edge[K], error) {
+	res := make([]Edge[K], 0)
+	s.outEdges.each(func(_ K, edges hamt[K, Edge[K]]) bool {
+		edges.each(func(_ K, edge Edge[K]) bool {
+			res = append(res, edge)
+			return true
+		})
+		return true
+	})
+
+	return res, nil
+}
+
+func (s *hamtStore[K, T]) InDegree(hash K) (int, error) {
+	if _, ok := s.vertices.get(hash); !ok {
+		return 0, ErrorVertextNotFound
+	}
+
+	return s.edges(s.inEdges, hash).len(), nil
+}
+
+func (s *hamtStore[K, T]) OutDegree(hash K) (int, error) {
+	if _, ok := s.vertices.get(hash); !ok {
+		return 0, ErrorVertextNotFound
+	}
+
+	return s.edges(s.outEdges, hash).len(), nil
+}
+
+func (s *hamtStore[K, T]) Neighbors(hash K) ([]K, error) {
+	return s.adjacent(s.outEdges, hash)
+}
+
+func (s *hamtStore[K, T]) Predecessors(hash K) ([]K, error) {
+	return s.adjacent(s.inEdges, hash)
+}
+
+func (s *hamtStore[K, T]) adjacent(m hamt[K, hamt[K, Edge[K]]], hash K) ([]K, error) {
+	if _, ok := s.vertices.get(hash); !ok {
+		return nil, ErrorVertextNotFound
+	}
+
+	edges := s.edges(m, hash)
+	res := make([]K, 0, edges.len())
+	edges.each(func(key K, _ Edge[K]) bool {
+		res = append(res, key)
+		return true
+	})
+
+	return res, nil
+}
+
+func (s *hamtStore[K, T]) OutEdges(hash K) ([]Edge[K], error) {
+	return s.incident(s.outEdges, hash)
+}
+
+func (s *hamtStore[K, T]) InEdges(hash K) ([]Edge[K], error) {
+	return s.incident(s.inEdges, hash)
+}
+
+func (s *hamtStore[K, T]) incident(m hamt[K, hamt[K, Edge[K]]], hash K) ([]Edge[K], error) {
+	if _, ok := s.vertices.get(hash); !ok {
+		return nil, ErrorVertextNotFound
+	}
+
+	edges := s.edges(m, hash)
+	res := make([]Edge[K], 0, edges.len())
+	edges.each(func(_ K, edge Edge[K]) bool {
+		res = append(res, edge)
+		return true
+	})
+
+	return res, nil
+}
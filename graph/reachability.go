@@ -0,0 +1,337 @@
+package graph
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+)
+
+// Все вершины, из которых есть путь в v. Сама v не входит, даже если лежит на цикле
+func Ancestors[K comparable, T any](g Graph[K, T], v K) ([]K, error) {
+	return reachableFrom(v, g.Predecessors)
+}
+
+// Все вершины, в которые есть путь из v. Сама v не входит, даже если лежит на цикле
+func Descendants[K comparable, T any](g Graph[K, T], v K) ([]K, error) {
+	return reachableFrom(v, g.Neighbors)
+}
+
+// Обход в ширину, соседей даёт next
+func reachableFrom[K comparable](start K, next func(K) ([]K, error)) ([]K, error) {
+	if _, err := next(start); err != nil {
+		return nil, err
+	}
+
+	visited := map[K]bool{start: true}
+	queue := []K{start}
+	res := make([]K, 0)
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		adjacencies, err := next(current)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, adjacency := range adjacencies {
+			if !visited[adjacency] {
+				visited[adjacency] = true
+				queue = append(queue, adjacency)
+				res = append(res, adjacency)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// Сколько случайных разметок интервалами хранит ReachabilityIndex
+const reachabilityLabelings = 3
+
+// Индекс достижимости для ациклического графа на интервальной разметке.
+// Каждая вершина получает несколько интервалов [low, post] от обходов в глубину
+// со случайным порядком детей. Если v достижима из u, интервал v вложен в интервал u
+// в каждой разметке, поэтому невложенность сразу даёт ответ "нет".
+// Вложенность в дереве первого обхода сразу даёт ответ "да".
+// Остальные запросы проверяются обходом в глубину, который отсекает вершины по интервалам.
+//
+// Памяти нужно O(k*n + m) для n вершин, m дуг и k разметок, поэтому индекс годится
+// и для больших графов. CanReach на большинстве запросов работает за O(k).
+// Индекс не следит за графом, после изменений его надо построить заново
+type ReachabilityIndex[K comparable] struct {
+	// Номер вершины в топологическом порядке. Дальше вершины обозначаются этими номерами
+	index map[K]int
+
+	// Дети вершины i: targets[offsets[i]:offsets[i+1]]
+	offsets []int
+	targets []int
+
+	// Интервалы вершин по разметкам: [low[l][i], post[l][i]]
+	low  [reachabilityLabelings][]int
+	post [reachabilityLabelings][]int
+	// Начало интервала по дереву первого обхода, для ответа "да"
+	treeLow []int
+
+	// Отметки посещённых вершин для обходов, свои у каждого параллельного запроса
+	marks sync.Pool
+}
+
+// Отметки вершин без очистки между обходами: вершина посещена, если её отметка равна stamp
+type visitMarks struct {
+	stamp uint32
+	marks []uint32
+}
+
+// Начинает новый обход
+func (r *ReachabilityIndex[K]) visits() *visitMarks {
+	v, ok := r.marks.Get().(*visitMarks)
+	if !ok {
+		v = &visitMarks{marks: make([]uint32, len(r.offsets)-1)}
+	}
+
+	v.stamp++
+	if v.stamp == 0 {
+		clear(v.marks)
+		v.stamp = 1
+	}
+
+	return v
+}
+
+// Отмечает вершину. false, если она уже была отмечена в этом обходе
+func (v *visitMarks) visit(i int) bool {
+	if v.marks[i] == v.stamp {
+		return false
+	}
+
+	v.marks[i] = v.stamp
+	return true
+}
+
+// Строит индекс. Для графа с циклом возвращает ErrorHasCycle
+func NewReachabilityIndex[K comparable, T any](g Graph[K, T]) (*ReachabilityIndex[K], error) {
+	order, err := TopologicalSort(g)
+	if err != nil {
+		return nil, err
+	}
+
+	adjacencyMap, err := g.AdjacencyMap()
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(order)
+
+	r := &ReachabilityIndex[K]{
+		index:   make(map[K]int, n),
+		offsets: make([]int, 1, n+1),
+	}
+
+	for i, vertex := range order {
+		r.index[vertex] = i
+	}
+
+	for _, vertex := range order {
+		start := len(r.targets)
+		for adjacency := range adjacencyMap[vertex] {
+			r.targets = append(r.targets, r.index[adjacency])
+		}
+		// По возрастанию номера: обход в CanReach первым берёт ребёнка, ближайшего к цели
+		slices.Sort(r.targets[start:])
+		r.offsets = append(r.offsets, len(r.targets))
+	}
+
+	// Порядок детей и корней случайный, но одинаковый от запуска к запуску
+	random := rand.New(rand.NewSource(1))
+
+	for l := range r.low {
+		r.label(l, random)
+	}
+
+	return r, nil
+}
+
+// Строит разметку l обходом в глубину без рекурсии
+func (r *ReachabilityIndex[K]) label(l int, random *rand.Rand) {
+	n := len(r.offsets) - 1
+
+	low := make([]int, n)
+	post := make([]int, n)
+	visited := make([]bool, n)
+
+	var treeLow []int
+	if l == 0 {
+		// Дети заканчиваются раньше родителя и опускают его начало
+		treeLow = make([]int, n)
+		for i := range treeLow {
+			treeLow[i] = n
+		}
+	}
+
+	// Перемешанная копия детей для этого обхода
+	targets := slices.Clone(r.targets)
+	for i := 0; i < n; i++ {
+		children := targets[r.offsets[i]:r.offsets[i+1]]
+		random.Shuffle(len(children), func(a, b int) {
+			children[a], children[b] = children[b], children[a]
+		})
+	}
+
+	type frame struct {
+		vertex int
+		next   int
+	}
+
+	rank := 0
+	stack := make([]frame, 0)
+
+	for _, root := range random.Perm(n) {
+		if visited[root] {
+			continue
+		}
+
+		visited[root] = true
+		stack = append(stack, frame{vertex: root, next: r.offsets[root]})
+
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+
+			if top.next < r.offsets[top.vertex+1] {
+				child := targets[top.next]
+				top.next++
+
+				if !visited[child] {
+					visited[child] = true
+					stack = append(stack, frame{vertex: child, next: r.offsets[child]})
+				}
+				continue
+			}
+
+			// Все дети закончены, в ациклическом графе обратных дуг нет
+			v := top.vertex
+			stack = stack[:len(stack)-1]
+
+			post[v] = rank
+			low[v] = rank
+			for _, child := range targets[r.offsets[v]:r.offsets[v+1]] {
+				low[v] = min(low[v], low[child])
+			}
+
+			if treeLow != nil {
+				treeLow[v] = min(treeLow[v], rank)
+				if len(stack) > 0 {
+					parent := stack[len(stack)-1].vertex
+					treeLow[parent] = min(treeLow[parent], treeLow[v])
+				}
+			}
+
+			rank++
+		}
+	}
+
+	r.low[l] = low
+	r.post[l] = post
+	if treeLow != nil {
+		r.treeLow = treeLow
+	}
+}
+
+// Могут ли интервалы пустить путь из i в j: проверка номеров и всех разметок
+func (r *ReachabilityIndex[K]) mayReach(i, j int) bool {
+	// В топологическом порядке потомки всегда идут позже
+	if j < i {
+		return false
+	}
+
+	for l := range r.low {
+		if r.low[l][j] < r.low[l][i] || r.post[l][j] > r.post[l][i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Лежит ли j в поддереве i первого обхода. Тогда путь из i в j точно есть
+func (r *ReachabilityIndex[K]) inTree(i, j int) bool {
+	return r.treeLow[i] <= r.post[0][j] && r.post[0][j] <= r.post[0][i]
+}
+
+// Есть ли путь из u в v. Вершина всегда достижима из самой себя
+func (r *ReachabilityIndex[K]) CanReach(u, v K) (bool, error) {
+	i, ok := r.index[u]
+	if !ok {
+		return false, ErrorVertextNotFound
+	}
+
+	j, ok := r.index[v]
+	if !ok {
+		return false, ErrorVertextNotFound
+	}
+
+	if i == j {
+		return true, nil
+	}
+
+	if !r.mayReach(i, j) {
+		return false, nil
+	}
+
+	if r.inTree(i, j) {
+		return true, nil
+	}
+
+	visited := r.visits()
+	defer r.marks.Put(visited)
+
+	visited.visit(i)
+	stack := []int{i}
+
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, child := range r.targets[r.offsets[current]:r.offsets[current+1]] {
+			if child == j || r.inTree(child, j) {
+				return true, nil
+			}
+
+			if r.mayReach(child, j) && visited.visit(child) {
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// Количество потомков вершины, не считая её саму. Считается обходом за O(n + m)
+func (r *ReachabilityIndex[K]) DescendantCount(v K) (int, error) {
+	i, ok := r.index[v]
+	if !ok {
+		return 0, ErrorVertextNotFound
+	}
+
+	visited := r.visits()
+	defer r.marks.Put(visited)
+
+	visited.visit(i)
+	stack := []int{i}
+	count := 0
+
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, child := range r.targets[r.offsets[current]:r.offsets[current+1]] {
+			if visited.visit(child) {
+				count++
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestReachabilityIndexMatchesDescendants(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+
+	for round := 0; round < 20; round++ {
+		g := New(IntHash, Directed())
+
+		n := 5 + random.Intn(60)
+		for i := 0; i < n; i++ {
+			_ = g.AddVertex(i)
+		}
+
+		// Дуги только вперёд, поэтому граф ациклический
+		for e := random.Intn(3 * n); e > 0; e-- {
+			u, v := random.Intn(n), random.Intn(n)
+			if u < v {
+				_ = g.AddEdge(u, v)
+			}
+		}
+
+		index, err := NewReachabilityIndex(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for u := 0; u < n; u++ {
+			descendants, err := Descendants(g, u)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			reachable := map[int]bool{u: true}
+			for _, v := range descendants {
+				reachable[v] = true
+			}
+
+			for v := 0; v < n; v++ {
+				got, err := index.CanReach(u, v)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != reachable[v] {
+					t.Fatalf("round %d: CanReach(%d, %d) = %v, want %v", round, u, v, got, reachable[v])
+				}
+			}
+
+			count, err := index.DescendantCount(u)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != len(descendants) {
+				t.Fatalf("round %d: DescendantCount(%d) = %d, want %d", round, u, count, len(descendants))
+			}
+		}
+	}
+}
+
+func TestReachabilityIndexRejectsCycle(t *testing.T) {
+	g := New(IntHash, Directed())
+	_ = g.AddVertex(1)
+	_ = g.AddVertex(2)
+	_ = g.AddEdge(1, 2)
+	_ = g.AddEdge(2, 1)
+
+	if _, err := NewReachabilityIndex(g); err != ErrorHasCycle {
+		t.Fatalf("err = %v, want ErrorHasCycle", err)
+	}
+}
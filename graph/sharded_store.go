@@ -0,0 +1,495 @@
+package graph
+
+import "runtime"
+
+// Хранилище в памяти, разбитое на шарды со своими блокировками.
+// Вершина живёт в шарде по хэшу своего ключа вместе с исходящими и входящими дугами,
+// поэтому запись в разные шарды идёт параллельно.
+// Дуга между шардами пишется под блокировками обоих шардов,
+// которые всегда берутся по возрастанию номера, поэтому взаимной блокировки нет.
+//
+// ListVertices и ListEdges проходят шарды по очереди и видят не один момент времени.
+// Согласованный вид всего хранилища даёт View, запросы по индексам идут через него же
+type ShardedStore[K comparable, T any] struct {
+	shards  []*MemoryStore[K, T]
+	shardOf func(K) uint64
+}
+
+type ShardedStoreOptions[K comparable] struct {
+	// Хэш ключа для выбора шарда
+	ShardHash func(K) uint64
+}
+
+// Своя функция выбора шарда, например для ключей-структур
+func ShardBy[K comparable](fn func(K) uint64) func(*ShardedStoreOptions[K]) {
+	return func(o *ShardedStoreOptions[K]) {
+		o.ShardHash = fn
+	}
+}
+
+// Создаёт хранилище из shards шардов.
+// При shards <= 0 шардов в четыре раза больше, чем GOMAXPROCS
+func NewShardedStore[K comparable, T any](shards int, options ...func(*ShardedStoreOptions[K])) *ShardedStore[K, T] {
+	opts := ShardedStoreOptions[K]{
+		ShardHash: keyHash[K],
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	if shards <= 0 {
+		shards = 4 * runtime.GOMAXPROCS(0)
+	}
+
+	s := &ShardedStore[K, T]{
+		shards:  make([]*MemoryStore[K, T], shards),
+		shardOf: opts.ShardHash,
+	}
+
+	for i := range s.shards {
+		s.shards[i] = NewMemoryStore[K, T]()
+	}
+
+	return s
+}
+
+func (s *ShardedStore[K, T]) NewEmpty() Store[K, T] {
+	return NewShardedStore[K, T](len(s.shards), ShardBy(s.shardOf))
+}
+
+func (s *ShardedStore[K, T]) index(hash K) int {
+	return int(s.shardOf(hash) % uint64(len(s.shards)))
+}
+
+func (s *ShardedStore[K, T]) shard(hash K) *MemoryStore[K, T] {
+	return s.shards[s.index(hash)]
+}
+
+// Блокирует на запись шарды двух вершин по возрастанию номера
+func (s *ShardedStore[K, T]) lockPair(i, j int) {
+	if i > j {
+		i, j = j, i
+	}
+
+	s.shards[i].lock.Lock()
+	if i != j {
+		s.shards[j].lock.Lock()
+	}
+}
+
+func (s *ShardedStore[K, T]) unlockPair(i, j int) {
+	s.shards[i].lock.Unlock()
+	if i != j {
+		s.shards[j].lock.Unlock()
+	}
+}
+
+// Блокирует все шарды на время fn
+func (s *ShardedStore[K, T]) Batch(fn func(tx Store[K, T]) error) error {
+	for _, shard := range s.shards {
+		shard.lock.Lock()
+	}
+
+	defer func() {
+		for _, shard := range s.shards {
+			shard.lock.Unlock()
+		}
+	}()
+
+	view := &ShardedStore[K, T]{
+		shards:  make([]*MemoryStore[K, T], len(s.shards)),
+		shardOf: s.shardOf,
+	}
+
+	for i, shard := range s.shards {
+		view.shards[i] = shard.view()
+	}
+
+	err := runTx[K, T](view, fn)
+
+	for i, shard := range s.shards {
+		shard.adopt(view.shards[i])
+	}
+
+	return err
+}
+
+// Блокирует только шарды вершин keys. Остальные шарды работают как обычно,
+// поэтому в fn атомарны лишь изменения вершин keys и дуг между ними
+func (s *ShardedStore[K, T]) BatchKeys(keys []K, fn func(tx Store[K, T]) error) error {
+	locked := make([]bool, len(s.shards))
+	for _, key := range keys {
+		locked[s.index(key)] = true
+	}
+
+	view := &ShardedStore[K, T]{
+		shards:  make([]*MemoryStore[K, T], len(s.shards)),
+		shardOf: s.shardOf,
+	}
+	copy(view.shards, s.shards)
+
+	// Проход по возрастанию номера задаёт общий порядок блокировок
+	for i, shard := range s.shards {
+		if locked[i] {
+			shard.lock.Lock()
+			view.shards[i] = shard.view()
+		}
+	}
+
+	defer func() {
+		for i, shard := range s.shards {
+			if locked[i] {
+				shard.lock.Unlock()
+			}
+		}
+	}()
+
+	err := runTx[K, T](view, fn)
+
+	for i, shard := range s.shards {
+		if locked[i] {
+			shard.adopt(view.shards[i])
+		}
+	}
+
+	return err
+}
+
+func (s *ShardedStore[K, T]) View(fn func(view Store[K, T]) error) error {
+	for _, shard := range s.shards {
+		shard.lock.RLock()
+	}
+
+	defer func() {
+		for _, shard := range s.shards {
+			shard.lock.RUnlock()
+		}
+	}()
+
+	view := &ShardedStore[K, T]{
+		shards:  make([]*MemoryStore[K, T], len(s.shards)),
+		shardOf: s.shardOf,
+	}
+
+	for i, shard := range s.shards {
+		view.shards[i] = shard.view()
+	}
+
+	return fn(view)
+}
+
+// Снимок всех шардов на один момент. Как и у MemoryStore, запись после снимка
+// копирует карты шарда, а release отпускает их
+func (s *ShardedStore[K, T]) Snapshot() (Store[K, T], func()) {
+	for _, shard := range s.shards {
+		shard.lock.Lock()
+	}
+
+	defer func() {
+		for _, shard := range s.shards {
+			shard.lock.Unlock()
+		}
+	}()
+
+	snapshot := &ShardedStore[K, T]{
+		shards:  make([]*MemoryStore[K, T], len(s.shards)),
+		shardOf: s.shardOf,
+	}
+	releases := make([]func(), len(s.shards))
+
+	for i, shard := range s.shards {
+		snapshot.shards[i], releases[i] = shard.pin()
+	}
+
+	return snapshot, func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+}
+
+func (s *ShardedStore[K, T]) AddVertex(hash K, value T, properties VertexProperties) error {
+	return s.shard(hash).AddVertex(hash, value, properties)
+}
+
+func (s *ShardedStore[K, T]) Vertex(hash K) (T, VertexProperties, error) {
+	return s.shard(hash).Vertex(hash)
+}
+
+// Все дуги вершины лежат в её шарде, поэтому проверка дуг и удаление под одной блокировкой
+func (s *ShardedStore[K, T]) RemoveVertex(hash K) error {
+	return s.shard(hash).RemoveVertex(hash)
+}
+
+func (s *ShardedStore[K, T]) EditVertex(hash K, properties VertexProperties) error {
+	return s.shard(hash).EditVertex(hash, properties)
+}
+
+func (s *ShardedStore[K, T]) ListVertices() ([]K, error) {
+	res := make([]K, 0)
+	for _, shard := range s.shards {
+		hashes, err := shard.ListVertices()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, hashes...)
+	}
+
+	return res, nil
+}
+
+func (s *ShardedStore[K, T]) VertexCount() (int, error) {
+	count := 0
+	for _, shard := range s.shards {
+		n, err := shard.VertexCount()
+		if err != nil {
+			return 0, err
+		}
+		count += n
+	}
+
+	return count, nil
+}
+
+// Исходящая половина дуги пишется в шард source, входящая в шард target.
+// В отличие от MemoryStore дуга к отсутствующей вершине не добавляется:
+// иначе параллельный RemoveVertex в другом шарде оставил бы висячую дугу
+func (s *ShardedStore[K, T]) AddEdge(source, target K, edge Edge[K]) error {
+	i, j := s.index(source), s.index(target)
+	s.lockPair(i, j)
+	defer s.unlockPair(i, j)
+
+	from, to := s.shards[i], s.shards[j]
+
+	if _, ok := from.vertices[source]; !ok {
+		return ErrorVertextNotFound
+	}
+
+	if _, ok := to.vertices[target]; !ok {
+		return ErrorVertextNotFound
+	}
+
+	from.own()
+	to.own()
+	from.setOut(source, target, edge)
+	to.setIn(source, target, edge)
+
+	return nil
+}
+
+func (s *ShardedStore[K, T]) EditEdge(source, target K, edge Edge[K]) error {
+	i, j := s.index(source), s.index(target)
+	s.lockPair(i, j)
+	defer s.unlockPair(i, j)
+
+	from, to := s.shards[i], s.shards[j]
+
+	if _, ok := from.outEdges[source][target]; !ok {
+		return ErrorEdgeNotFound
+	}
+
+	from.own()
+	to.own()
+	from.setOut(source, target, edge)
+	to.setIn(source, target, edge)
+
+	return nil
+}
+
+func (s *ShardedStore[K, T]) RemoveEdge(source, target K) error {
+	i, j := s.index(source), s.index(target)
+	s.lockPair(i, j)
+	defer s.unlockPair(i, j)
+
+	from, to := s.shards[i], s.shards[j]
+
+	if _, ok := from.outEdges[source][target]; !ok {
+		return nil
+	}
+
+	from.own()
+	to.own()
+	from.removePairOut(source, target)
+	to.removePairIn(source, target)
+
+	return nil
+}
+
+func (s *ShardedStore[K, T]) Edge(source, target K) (Edge[K], error) {
+	return s.shard(source).Edge(source, target)
+}
+
+func (s *ShardedStore[K, T]) ListEdges() ([]Edge[K], error) {
+	res := make([]Edge[K], 0)
+	for _, shard := range s.shards {
+		edges, err := shard.ListEdges()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, edges...)
+	}
+
+	return res, nil
+}
+
+func (s *ShardedStore[K, T]) InDegree(hash K) (int, error) {
+	return s.shard(hash).InDegree(hash)
+}
+
+func (s *ShardedStore[K, T]) OutDegree(hash K) (int, error) {
+	return s.shard(hash).OutDegree(hash)
+}
+
+func (s *ShardedStore[K, T]) Neighbors(hash K) ([]K, error) {
+	return s.shard(hash).Neighbors(hash)
+}
+
+func (s *ShardedStore[K, T]) Predecessors(hash K) ([]K, error) {
+	return s.shard(hash).Predecessors(hash)
+}
+
+func (s *ShardedStore[K, T]) OutEdges(hash K) ([]Edge[K], error) {
+	return s.shard(hash).OutEdges(hash)
+}
+
+func (s *ShardedStore[K, T]) InEdges(hash K) ([]Edge[K], error) {
+	return s.shard(hash).InEdges(hash)
+}
+
+// Дуги мультиграфа. Половины пишутся так же, как в AddEdge, а индекс ID живёт в шарде source
+
+func (s *ShardedStore[K, T]) AddParallelEdge(source, target K, edge Edge[K]) error {
+	i, j := s.index(source), s.index(target)
+	s.lockPair(i, j)
+	defer s.unlockPair(i, j)
+
+	from, to := s.shards[i], s.shards[j]
+
+	if _, ok := from.vertices[source]; !ok {
+		return ErrorVertextNotFound
+	}
+
+	if _, ok := to.vertices[target]; !ok {
+		return ErrorVertextNotFound
+	}
+
+	if from.hasParallel(source, target, edge.Properties.ID) {
+		return ErrorEdgeExists
+	}
+
+	from.own()
+	to.own()
+	from.addOut(source, target, edge)
+	to.addIn(source, target, edge)
+
+	return nil
+}
+
+func (s *ShardedStore[K, T]) EditParallelEdge(source, target K, edge Edge[K]) error {
+	i, j := s.index(source), s.index(target)
+	s.lockPair(i, j)
+	defer s.unlockPair(i, j)
+
+	from, to := s.shards[i], s.shards[j]
+
+	if !from.hasParallel(source, target, edge.Properties.ID) {
+		return ErrorEdgeNotFound
+	}
+
+	from.own()
+	to.own()
+	from.editOut(source, target, edge)
+	to.editIn(source, target, edge)
+
+	return nil
+}
+
+func (s *ShardedStore[K, T]) RemoveParallelEdge(source, target K, id uint64) error {
+	i, j := s.index(source), s.index(target)
+	s.lockPair(i, j)
+	defer s.unlockPair(i, j)
+
+	from, to := s.shards[i], s.shards[j]
+
+	if !from.hasParallel(source, target, id) {
+		return ErrorEdgeNotFound
+	}
+
+	from.own()
+	to.own()
+	from.removeOut(source, target, id)
+	to.removeIn(source, target, id)
+
+	return nil
+}
+
+func (s *ShardedStore[K, T]) EdgesBetween(source, target K) ([]Edge[K], error) {
+	return s.shard(source).EdgesBetween(source, target)
+}
+
+// Шард дуги по ID неизвестен, поэтому ищем во всех по очереди
+func (s *ShardedStore[K, T]) EdgeByID(id uint64) (Edge[K], error) {
+	for _, shard := range s.shards {
+		edge, err := shard.EdgeByID(id)
+		if err == nil {
+			return edge, nil
+		}
+	}
+
+	return Edge[K]{}, ErrorEdgeNotFound
+}
+
+// Индексы у каждого шарда свои, запрос собирает ответ со всех шардов сразу
+
+func (s *ShardedStore[K, T]) CreateIndex(attribute string) error {
+	for _, shard := range s.shards {
+		if err := shard.CreateIndex(attribute); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ShardedStore[K, T]) CreateWeightIndex() error {
+	for _, shard := range s.shards {
+		if err := shard.CreateWeightIndex(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ShardedStore[K, T]) VerticesByAttribute(attribute, value string) ([]K, error) {
+	return s.collect(func(shard *MemoryStore[K, T]) ([]K, error) {
+		return shard.VerticesByAttribute(attribute, value)
+	})
+}
+
+func (s *ShardedStore[K, T]) VerticesByWeight(min, max float64) ([]K, error) {
+	return s.collect(func(shard *MemoryStore[K, T]) ([]K, error) {
+		return shard.VerticesByWeight(min, max)
+	})
+}
+
+// Собирает ответ со всех шардов под их блокировками на чтение,
+// поэтому запрос видит один момент времени, как View
+func (s *ShardedStore[K, T]) collect(query func(shard *MemoryStore[K, T]) ([]K, error)) ([]K, error) {
+	res := make([]K, 0)
+
+	err := s.View(func(view Store[K, T]) error {
+		for _, shard := range view.(*ShardedStore[K, T]).shards {
+			hashes, err := query(shard)
+			if err != nil {
+				return err
+			}
+			res = append(res, hashes...)
+		}
+
+		return nil
+	})
+
+	return res, err
+}
@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+const (
+	benchmarkShards   = 16
+	benchmarkVertices = 1 << 14
+)
+
+// Хранилище с заполненными вершинами. Шард вершины k это k % benchmarkShards
+func benchmarkShardedStores(b *testing.B) []struct {
+	name  string
+	store Store[int, int]
+} {
+	sharded := NewShardedStore[int, int](benchmarkShards, ShardBy(func(k int) uint64 {
+		return uint64(k)
+	}))
+
+	stores := []struct {
+		name  string
+		store Store[int, int]
+	}{
+		{"sharded", sharded},
+		{"memory", NewMemoryStore[int, int]()},
+	}
+
+	for _, s := range stores {
+		for i := 0; i < benchmarkVertices; i++ {
+			if err := s.store.AddVertex(i, i, VertexProperties{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	return stores
+}
+
+// Параллельная запись дуг. Сравнивать при разном числе потоков:
+//
+//	go test -run ^$ -bench AddEdge -cpu 1,2,4,8 ./graph
+func BenchmarkShardedStoreAddEdge(b *testing.B) {
+	edges := []struct {
+		name string
+		// Конец дуги из вершины source
+		target func(source int) int
+	}{
+		// Соседний номер лежит в соседнем шарде
+		{"cross-shard", func(source int) int { return (source + 1) % benchmarkVertices }},
+		// Номер через benchmarkShards лежит в том же шарде
+		{"same-shard", func(source int) int { return (source + benchmarkShards) % benchmarkVertices }},
+	}
+
+	for _, s := range benchmarkShardedStores(b) {
+		for _, edge := range edges {
+			b.Run(fmt.Sprintf("%s/%s", s.name, edge.name), func(b *testing.B) {
+				var workers atomic.Int64
+
+				b.ReportAllocs()
+				b.RunParallel(func(pb *testing.PB) {
+					// Каждая горутина начинает со своего места, чтобы не писать одни и те же дуги
+					source := int(workers.Add(1)*7919) % benchmarkVertices
+
+					for pb.Next() {
+						target := edge.target(source)
+						if err := s.store.AddEdge(source, target, Edge[int]{Source: source, Target: target}); err != nil {
+							b.Fatal(err)
+						}
+
+						source = (source + 1) % benchmarkVertices
+					}
+				})
+			})
+		}
+	}
+}
+
+func TestShardedStoreIndexQueries(t *testing.T) {
+	g := NewWithStore(IntHash, Store[int, int](NewShardedStore[int, int](4)), Directed())
+
+	if err := CreateVertexIndex(g, "parity"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateVertexWeightIndex(g); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 20; i++ {
+		if err := g.AddVertex(i, VertexWeight(i), vertexAttribute("parity", fmt.Sprint(i%2))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	odd, err := VerticesWhere(g, "parity", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(odd)
+	if want := []int{1, 3, 5, 7, 9, 11, 13, 15, 17, 19}; !slices.Equal(odd, want) {
+		t.Fatalf("VerticesWhere = %v, ожидалось %v", odd, want)
+	}
+
+	heavy, err := VerticesWithWeight(g, 15, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(heavy)
+	if want := []int{15, 16, 17, 18, 19}; !slices.Equal(heavy, want) {
+		t.Fatalf("VerticesWithWeight = %v, ожидалось %v", heavy, want)
+	}
+}
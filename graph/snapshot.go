@@ -0,0 +1,154 @@
+package graph
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Хранилище, которое умеет делать дешёвые снимки.
+// Снимок не меняется при записи в хранилище и доступен только для чтения.
+// release отпускает снимок, после этого хранилище может освободить его данные
+type SnapshotStore[K comparable, T any] interface {
+	Snapshot() (snapshot Store[K, T], release func())
+}
+
+// Граф, закреплённый на версии данных в момент Graph.Snapshot.
+// Запись в исходный граф идёт параллельно и на снимок не влияет,
+// поэтому долгие алгоритмы на снимке видят один и тот же граф.
+// Все изменяющие методы возвращают ErrorGraphReadOnly.
+//
+// После работы снимок нужно отпустить через Release. Если забыть,
+// версия освободится, когда снимок заберёт сборщик мусора
+type Snapshot[K comparable, T any] struct {
+	Graph[K, T]
+
+	once    sync.Once
+	release func()
+}
+
+func newSnapshot[K comparable, T any](g Graph[K, T], release func()) *Snapshot[K, T] {
+	s := &Snapshot[K, T]{
+		Graph:   g,
+		release: release,
+	}
+
+	runtime.SetFinalizer(s, func(s *Snapshot[K, T]) {
+		s.Release()
+	})
+
+	return s
+}
+
+// Отпускает версию данных. Читать снимок после этого нельзя
+func (s *Snapshot[K, T]) Release() {
+	s.once.Do(func() {
+		runtime.SetFinalizer(s, nil)
+		s.release()
+	})
+}
+
+// Снимок хранилища. Хранилище без SnapshotStore копируется целиком
+func snapshotStore[K comparable, T any](s Store[K, T]) (Store[K, T], func(), error) {
+	if snapshotter, ok := s.(SnapshotStore[K, T]); ok {
+		snapshot, release := snapshotter.Snapshot()
+		return snapshot, release, nil
+	}
+
+	snapshot := NewMemoryStore[K, T]()
+
+	err := runView(s, func(view Store[K, T]) error {
+		vertices, err := view.ListVertices()
+		if err != nil {
+			return err
+		}
+
+		for _, hash := range vertices {
+			value, properties, err := view.Vertex(hash)
+			if err != nil {
+				return err
+			}
+
+			if err := snapshot.AddVertex(hash, value, properties); err != nil {
+				return err
+			}
+		}
+
+		edges, err := view.ListEdges()
+		if err != nil {
+			return err
+		}
+
+		for _, edge := range edges {
+			if err := snapshot.AddEdge(edge.Source, edge.Target, edge); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return snapshot, func() {}, nil
+}
+
+func (s *Snapshot[K, T]) AddVertex(_ T, _ ...func(*VertexProperties)) error {
+	return ErrorGraphReadOnly
+}
+
+func (s *Snapshot[K, T]) RemoveVertex(_ K) error {
+	return ErrorGraphReadOnly
+}
+
+func (s *Snapshot[K, T]) RemoveVertexCascade(_ K) ([]Edge[K], error) {
+	return nil, ErrorGraphReadOnly
+}
+
+func (s *Snapshot[K, T]) EditVertex(_ K, _ ...func(*VertexProperties)) error {
+	return ErrorGraphReadOnly
+}
+
+func (s *Snapshot[K, T]) AddEdge(_, _ K, _ ...func(*EdgeProperties)) error {
+	return ErrorGraphReadOnly
+}
+
+func (s *Snapshot[K, T]) AddEdgeWithID(_, _ K, _ ...func(*EdgeProperties)) (uint64, error) {
+	return 0, ErrorGraphReadOnly
+}
+
+func (s *Snapshot[K, T]) EditEdgeByID(_ uint64, _ ...func(properties *EdgeProperties)) error {
+	return ErrorGraphReadOnly
+}
+
+func (s *Snapshot[K, T]) RemoveEdgeByID(_ uint64) error {
+	return ErrorGraphReadOnly
+}
+
+func (s *Snapshot[K, T]) AddVerticesFrom(_ Graph[K, T]) error {
+	return ErrorGraphReadOnly
+}
+
+func (s *Snapshot[K, T]) AddEdgesFrom(_ Graph[K, T]) error {
+	return ErrorGraphReadOnly
+}
+
+func (s *Snapshot[K, T]) EditEdge(_, _ K, _ ...func(properties *EdgeProperties)) error {
+	return ErrorGraphReadOnly
+}
+
+func (s *Snapshot[K, T]) RemoveEdge(_, _ K) error {
+	return ErrorGraphReadOnly
+}
+
+func (s *Snapshot[K, T]) Batch(_ func(tx Graph[K, T]) error) error {
+	return ErrorGraphReadOnly
+}
+
+func (s *Snapshot[K, T]) hashFunc() Hash[K, T] {
+	return hashOf(s.Graph)
+}
+
+func (s *Snapshot[K, T]) storage() Store[K, T] {
+	return storeOf(s.Graph)
+}
@@ -0,0 +1,199 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+)
+
+// Хранилища со своим Snapshot и без него: DenseIntStore копируется целиком
+var snapshotStores = []struct {
+	name string
+	new  func(t *testing.T) Store[int, int]
+}{
+	{"memory", func(_ *testing.T) Store[int, int] { return NewMemoryStore[int, int]() }},
+	{"sharded", func(_ *testing.T) Store[int, int] { return NewShardedStore[int, int](4) }},
+	{"dense", func(_ *testing.T) Store[int, int] { return NewDenseIntStore[int]() }},
+	{"file", func(t *testing.T) Store[int, int] {
+		s := openFileStore(t, t.TempDir(), SyncNone())
+		t.Cleanup(func() { s.Close() })
+		return s
+	}},
+}
+
+// Цепочка 1 -> 2 -> 3 -> 4 с атрибутами у вершин и дуг
+func snapshotGraph(t *testing.T, store Store[int, int], options ...func(*Traits)) Graph[int, int] {
+	t.Helper()
+
+	g := NewWithStore(IntHash, store, options...)
+	for i := 1; i <= 4; i++ {
+		if err := g.AddVertex(i, VertexWeight(i), vertexAttribute("color", "red")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 1; i < 4; i++ {
+		if err := g.AddEdge(i, i+1, EdgeWeight(i), edgeAttribute("kind", "next")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return g
+}
+
+func TestSnapshotIgnoresLaterWrites(t *testing.T) {
+	for _, store := range snapshotStores {
+		for _, kind := range []struct {
+			name    string
+			options []func(*Traits)
+		}{
+			{"directed", []func(*Traits){Directed()}},
+			{"undirected", nil},
+		} {
+			t.Run(store.name+"/"+kind.name, func(t *testing.T) {
+				g := snapshotGraph(t, store.new(t), kind.options...)
+
+				snapshot, err := g.Snapshot()
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer snapshot.Release()
+				want := storeState[int, int](t, storeOf[int, int](snapshot))
+
+				// Меняются и сами значения, и вложенные карты атрибутов
+				if err := g.AddVertex(5); err != nil {
+					t.Fatal(err)
+				}
+				if err := g.AddEdge(4, 5); err != nil {
+					t.Fatal(err)
+				}
+				if err := g.EditEdge(1, 2, EdgeWeight(100), edgeAttribute("kind", "edited")); err != nil {
+					t.Fatal(err)
+				}
+				if err := g.EditVertex(2, VertexWeight(200), vertexAttribute("color", "blue")); err != nil {
+					t.Fatal(err)
+				}
+				if err := g.RemoveEdge(2, 3); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := g.RemoveVertexCascade(1); err != nil {
+					t.Fatal(err)
+				}
+
+				if got := storeState[int, int](t, storeOf[int, int](snapshot)); got != want {
+					t.Fatalf("снимок изменился:\n%s\nбыл:\n%s", got, want)
+				}
+
+				edge, err := snapshot.Edge(1, 2)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if edge.Properties.Weight != 1 || edge.Properties.Attributes["kind"] != "next" {
+					t.Fatalf("дуга 1 -> 2 в снимке %+v", edge.Properties)
+				}
+
+				_, properties, err := snapshot.VertexWithProperties(2)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if properties.Weight != 2 || properties.Attributes["color"] != "red" {
+					t.Fatalf("вершина 2 в снимке %+v", properties)
+				}
+
+				if _, err := snapshot.Vertex(5); !errors.Is(err, ErrorVertextNotFound) {
+					t.Fatalf("вершина 5 попала в снимок: %v", err)
+				}
+
+				// Новый снимок видит текущее состояние
+				current, err := g.Snapshot()
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer current.Release()
+
+				if got, want := storeState[int, int](t, storeOf[int, int](current)), storeState[int, int](t, storeOf(g)); got != want {
+					t.Fatalf("новый снимок:\n%s\nграф:\n%s", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestSnapshotSurvivesRemoveVertex(t *testing.T) {
+	for _, store := range snapshotStores {
+		t.Run(store.name, func(t *testing.T) {
+			g := snapshotGraph(t, store.new(t), Directed())
+
+			snapshot, err := g.Snapshot()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer snapshot.Release()
+
+			if err := g.RemoveEdge(3, 4); err != nil {
+				t.Fatal(err)
+			}
+			if err := g.RemoveVertex(4); err != nil {
+				t.Fatal(err)
+			}
+			// Ключ занимает новая вершина с другими свойствами
+			if err := g.AddVertex(4, VertexWeight(40)); err != nil {
+				t.Fatal(err)
+			}
+
+			_, properties, err := snapshot.VertexWithProperties(4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if properties.Weight != 4 || properties.Attributes["color"] != "red" {
+				t.Fatalf("вершина 4 в снимке %+v", properties)
+			}
+
+			predecessors, err := snapshot.Predecessors(4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(predecessors) != 1 || predecessors[0] != 3 {
+				t.Fatalf("предшественники 4 в снимке %v", predecessors)
+			}
+
+			if order, _ := snapshot.Order(); order != 4 {
+				t.Fatalf("Order() снимка = %d", order)
+			}
+		})
+	}
+}
+
+func TestSnapshotReadOnly(t *testing.T) {
+	g := snapshotGraph(t, NewMemoryStore[int, int](), Directed())
+
+	snapshot, err := g.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snapshot.Release()
+
+	if err := snapshot.AddVertex(9); !errors.Is(err, ErrorGraphReadOnly) {
+		t.Fatalf("AddVertex вернул %v", err)
+	}
+	if err := snapshot.RemoveEdge(1, 2); !errors.Is(err, ErrorGraphReadOnly) {
+		t.Fatalf("RemoveEdge вернул %v", err)
+	}
+	if err := snapshot.Batch(func(_ Graph[int, int]) error { return nil }); !errors.Is(err, ErrorGraphReadOnly) {
+		t.Fatalf("Batch вернул %v", err)
+	}
+
+	// Откатившийся Batch в графе снимок тоже не трогает
+	want := storeState[int, int](t, storeOf[int, int](snapshot))
+	failed := errors.New("откат")
+	err = g.Batch(func(tx Graph[int, int]) error {
+		if err := tx.EditEdge(1, 2, EdgeWeight(7)); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("Batch вернул %v", err)
+	}
+	if got := storeState[int, int](t, storeOf[int, int](snapshot)); got != want {
+		t.Fatalf("снимок изменился:\n%s\nбыл:\n%s", got, want)
+	}
+}
@@ -1,7 +1,11 @@
 package graph
 
 import (
+	"maps"
+	"slices"
+	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 type Store[K comparable, T any] interface {
@@ -11,6 +15,8 @@ type Store[K comparable, T any] interface {
 	Vertex(hash K) (T, VertexProperties, error)
 	// Удаление вершины по ключу
 	RemoveVertex(hash K) error
+	// Заменяет свойства вершины
+	EditVertex(hash K, properties VertexProperties) error
 	// Возврашает список вершин
 	ListVertices() ([]K, error)
 	// Козвршает количество вершин в грфае
@@ -25,29 +31,232 @@ type Store[K comparable, T any] interface {
 	Edge(sourceHash, targetHash K) (Edge[K], error)
 	// Возврашает список всег дуг в графе
 	ListEdges() ([]Edge[K], error)
+	// Количество дуг, входящих в вершину
+	InDegree(hash K) (int, error)
+	// Количество дуг, выходящих из вершины
+	OutDegree(hash K) (int, error)
+	// Вершины, в которые ведут дуги из hash
+	Neighbors(hash K) ([]K, error)
+	// Вершины, из которых ведут дуги в hash
+	Predecessors(hash K) ([]K, error)
+	// Дуги, выходящие из вершины
+	OutEdges(hash K) ([]Edge[K], error)
+	// Дуги, входящие в вершину
+	InEdges(hash K) ([]Edge[K], error)
 }
 
-// Структура хранения графа
-type store[K comparable, T any] struct {
+// Хранилище, которое умеет создавать пустое хранилище своего вида.
+// Clone и NewLike используют его, чтобы копия графа хранилась так же, как оригинал.
+// Если хранилище его не реализует, копия создаётся в памяти
+type StoreFactory[K comparable, T any] interface {
+	NewEmpty() Store[K, T]
+}
+
+// Хранилище графа в памяти. Используется по умолчанию в New
+type MemoryStore[K comparable, T any] struct {
 	lock             sync.RWMutex
 	vertices         map[K]T
-	VertexProperties map[K]VertexProperties
+	vertexProperties map[K]VertexProperties
 
 	inEdges  map[K]map[K]Edge[K] // target -> source
 	outEdges map[K]map[K]Edge[K] //source -> target
+
+	// Дуги мультиграфа сверх первой для пары вершин. Первая лежит в outEdges и inEdges
+	parallelIn  map[K]map[K][]Edge[K]
+	parallelOut map[K]map[K][]Edge[K]
+	// Пара вершин дуги мультиграфа по её ID
+	edgeIDs map[uint64]tuple[K]
+
+	// Индексы вершин: атрибут -> значение -> вершины. nil, пока индексов нет
+	attributeIndex map[string]map[string]map[K]struct{}
+	// Вершины по возрастанию веса, если индекс по весу объявлен
+	weightIndex   []weightEntry[K]
+	weightIndexed bool
+
+	// Версия текущих карт, если их держат снимки
+	pinned *memoryVersion
+	// Карты дуг, скопированные после последнего снимка. nil значит, что все карты свои
+	ownedIn  map[K]struct{}
+	ownedOut map[K]struct{}
+
+	// Снимок: карты общие с живым хранилищем, писать в них нельзя
+	readOnly bool
 }
 
-// Конструкт инициализации графа
-func newStore[K comparable, T any]() Store[K, T] {
-	return &store[K, T]{
+// Сколько снимков держат одну версию карт
+type memoryVersion struct {
+	pins atomic.Int32
+}
+
+// Конструкт инициализации хранилища
+func NewMemoryStore[K comparable, T any]() *MemoryStore[K, T] {
+	return &MemoryStore[K, T]{
 		vertices:         make(map[K]T),
-		VertexProperties: make(map[K]VertexProperties),
+		vertexProperties: make(map[K]VertexProperties),
 		inEdges:          make(map[K]map[K]Edge[K]),
 		outEdges:         make(map[K]map[K]Edge[K]),
 	}
 }
 
-func (s *store[K, T]) AddVertex(key K, value T, props VertexProperties) error {
+func (s *MemoryStore[K, T]) NewEmpty() Store[K, T] {
+	return NewMemoryStore[K, T]()
+}
+
+// Блокирует хранилище на всё время fn.
+// fn получает вид на те же карты со своей блокировкой, поэтому может вызывать любые методы
+func (s *MemoryStore[K, T]) Batch(fn func(tx Store[K, T]) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	view := s.view()
+	err := runTx[K, T](view, fn)
+	s.adopt(view)
+
+	return err
+}
+
+// Согласованное чтение: пока fn работает, изменения ждут.
+// Через view можно только читать
+func (s *MemoryStore[K, T]) View(fn func(view Store[K, T]) error) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return fn(s.view())
+}
+
+// Хранилище поверх тех же карт, но со своей блокировкой.
+// Вызывать только под s.lock
+func (s *MemoryStore[K, T]) view() *MemoryStore[K, T] {
+	return &MemoryStore[K, T]{
+		vertices:         s.vertices,
+		vertexProperties: s.vertexProperties,
+		inEdges:          s.inEdges,
+		outEdges:         s.outEdges,
+		parallelIn:       s.parallelIn,
+		parallelOut:      s.parallelOut,
+		edgeIDs:          s.edgeIDs,
+		attributeIndex:   s.attributeIndex,
+		weightIndex:      s.weightIndex,
+		weightIndexed:    s.weightIndexed,
+		pinned:           s.pinned,
+		ownedIn:          s.ownedIn,
+		ownedOut:         s.ownedOut,
+		readOnly:         s.readOnly,
+	}
+}
+
+// Забирает карты из вида после записи через него: вид мог их скопировать.
+// Вызывать только под s.lock
+func (s *MemoryStore[K, T]) adopt(view *MemoryStore[K, T]) {
+	s.vertices = view.vertices
+	s.vertexProperties = view.vertexProperties
+	s.inEdges = view.inEdges
+	s.outEdges = view.outEdges
+	s.parallelIn = view.parallelIn
+	s.parallelOut = view.parallelOut
+	s.edgeIDs = view.edgeIDs
+	s.attributeIndex = view.attributeIndex
+	s.weightIndex = view.weightIndex
+	s.weightIndexed = view.weightIndexed
+	s.pinned = view.pinned
+	s.ownedIn = view.ownedIn
+	s.ownedOut = view.ownedOut
+}
+
+// Снимок хранилища. Запись после снимка копирует карты, поэтому снимок не меняется.
+// Снимок только для чтения. После release старые карты забирает сборщик мусора,
+// а если до release записей не было, карты не копируются вовсе
+func (s *MemoryStore[K, T]) Snapshot() (Store[K, T], func()) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.pin()
+}
+
+// Закрепляет текущие карты за снимком. Вызывать только под s.lock
+func (s *MemoryStore[K, T]) pin() (*MemoryStore[K, T], func()) {
+	if s.pinned == nil {
+		s.pinned = &memoryVersion{}
+	}
+
+	version := s.pinned
+	version.pins.Add(1)
+
+	snapshot := &MemoryStore[K, T]{
+		vertices:         s.vertices,
+		vertexProperties: s.vertexProperties,
+		inEdges:          s.inEdges,
+		outEdges:         s.outEdges,
+		parallelIn:       s.parallelIn,
+		parallelOut:      s.parallelOut,
+		edgeIDs:          s.edgeIDs,
+		attributeIndex:   s.attributeIndex,
+		weightIndex:      s.weightIndex,
+		weightIndexed:    s.weightIndexed,
+		readOnly:         true,
+	}
+
+	var once sync.Once
+	return snapshot, func() {
+		once.Do(func() {
+			version.pins.Add(-1)
+		})
+	}
+}
+
+// Готовит карты к записи. Если их держит снимок, дальше пишем в копию.
+// Копируются только внешние карты, карты дуг вершин копируются при первой записи в них.
+// Параллельных дуг обычно мало, их карты копируются целиком, как и индексы вершин
+func (s *MemoryStore[K, T]) own() {
+	if s.pinned == nil {
+		return
+	}
+
+	if s.pinned.pins.Load() > 0 {
+		s.vertices = maps.Clone(s.vertices)
+		s.vertexProperties = maps.Clone(s.vertexProperties)
+		s.inEdges = maps.Clone(s.inEdges)
+		s.outEdges = maps.Clone(s.outEdges)
+		s.parallelIn = cloneParallel(s.parallelIn)
+		s.parallelOut = cloneParallel(s.parallelOut)
+		s.edgeIDs = maps.Clone(s.edgeIDs)
+		s.attributeIndex = cloneAttributeIndex(s.attributeIndex)
+		s.weightIndex = slices.Clone(s.weightIndex)
+		s.ownedIn = make(map[K]struct{})
+		s.ownedOut = make(map[K]struct{})
+	}
+
+	s.pinned = nil
+}
+
+// Карта исходящих дуг source, в которую можно писать
+func (s *MemoryStore[K, T]) outFor(source K) map[K]Edge[K] {
+	return ownEdges(s.outEdges, s.ownedOut, source)
+}
+
+// Карта входящих дуг target, в которую можно писать
+func (s *MemoryStore[K, T]) inFor(target K) map[K]Edge[K] {
+	return ownEdges(s.inEdges, s.ownedIn, target)
+}
+
+func ownEdges[K comparable](edges map[K]map[K]Edge[K], owned map[K]struct{}, hash K) map[K]Edge[K] {
+	res, ok := edges[hash]
+	if !ok {
+		res = make(map[K]Edge[K])
+		edges[hash] = res
+	} else if _, mine := owned[hash]; owned != nil && !mine {
+		res = maps.Clone(res)
+		edges[hash] = res
+	}
+
+	if owned != nil {
+		owned[hash] = struct{}{}
+	}
+
+	return res
+}
+
+func (s *MemoryStore[K, T]) AddVertex(key K, value T, props VertexProperties) error {
 	// Против гонки за ресурсами
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -57,13 +266,31 @@ func (s *store[K, T]) AddVertex(key K, value T, props VertexProperties) error {
 		return ErrorVertexExists
 	}
 
+	s.own()
 	s.vertices[key] = value
-	s.VertexProperties[key] = props
+	s.vertexProperties[key] = props
+	s.indexVertex(key, props)
+
+	return nil
+}
+
+func (s *MemoryStore[K, T]) EditVertex(key K, props VertexProperties) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.vertices[key]; !ok {
+		return ErrorVertextNotFound
+	}
+
+	s.own()
+	s.unindexVertex(key, s.vertexProperties[key])
+	s.vertexProperties[key] = props
+	s.indexVertex(key, props)
 
 	return nil
 }
 
-func (s *store[K, T]) ListVertices() ([]K, error) {
+func (s *MemoryStore[K, T]) ListVertices() ([]K, error) {
 	// Блокируем чтение
 	s.lock.RLock()
 	defer s.lock.RUnlock()
@@ -76,7 +303,7 @@ func (s *store[K, T]) ListVertices() ([]K, error) {
 	return hashes, nil
 }
 
-func (s *store[K, T]) VertexCount() (int, error) {
+func (s *MemoryStore[K, T]) VertexCount() (int, error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
@@ -84,7 +311,7 @@ func (s *store[K, T]) VertexCount() (int, error) {
 	return len(s.vertices), nil
 }
 
-func (s *store[K, T]) Vertex(key K) (T, VertexProperties, error) {
+func (s *MemoryStore[K, T]) Vertex(key K) (T, VertexProperties, error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
@@ -93,20 +320,22 @@ func (s *store[K, T]) Vertex(key K) (T, VertexProperties, error) {
 		return value, VertexProperties{}, ErrorVertextNotFound
 	}
 
-	props := s.VertexProperties[key]
+	props := s.vertexProperties[key]
 
 	return value, props, nil
 }
 
-func (s *store[K, T]) RemoveVertex(key K) error {
-	s.lock.RLock()
-	defer s.lock.RUnlock()
+func (s *MemoryStore[K, T]) RemoveVertex(key K) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 
 	// Проверка на существование вершины
 	if _, ok := s.vertices[key]; !ok {
 		return ErrorVertextNotFound
 	}
 
+	s.own()
+
 	// Проверка на дуги
 	if edges, ok := s.inEdges[key]; ok {
 		// Если дуг больше нуля, сообщаем об этом и ничего не делаем
@@ -126,61 +355,66 @@ func (s *store[K, T]) RemoveVertex(key K) error {
 		delete(s.outEdges, key)
 	}
 
+	delete(s.parallelIn, key)
+	delete(s.parallelOut, key)
+	delete(s.ownedIn, key)
+	delete(s.ownedOut, key)
+	s.unindexVertex(key, s.vertexProperties[key])
 	delete(s.vertices, key)
-	delete(s.VertexProperties, key)
+	delete(s.vertexProperties, key)
 
 	return nil
 }
 
-func (s *store[K, T]) AddEdge(source, target K, edge Edge[K]) error {
+func (s *MemoryStore[K, T]) AddEdge(source, target K, edge Edge[K]) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	// Если дуг нету, инициализируем новую дугу
-	if _, ok := s.outEdges[source]; !ok {
-		s.outEdges[source] = make(map[K]Edge[K])
-	}
+	s.own()
 
-	// Иначе перезапишем
-	s.outEdges[source][target] = edge
+	// Если дуг нету, карта создастся, иначе дуга перезапишется
+	s.setOut(source, target, edge)
 
 	// Для направленых внутрб тоже самое
-	if _, ok := s.inEdges[target]; !ok {
-		s.inEdges[target] = make(map[K]Edge[K])
-	}
-
-	s.inEdges[target][source] = edge
+	s.setIn(source, target, edge)
 
 	return nil
 }
 
-func (s *store[K, T]) EditEdge(soruce, target K, edge Edge[K]) error {
-	// Проверяем что такая дуг существует и не соеденяет нужные вершины
-	if _, err := s.Edge(soruce, target); err != nil {
-		return err
-	}
-
+func (s *MemoryStore[K, T]) EditEdge(soruce, target K, edge Edge[K]) error {
+	// Проверка и запись под одной блокировкой, иначе дугу могут удалить между ними
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	// Проверяем что такая дуг существует и не соеденяет нужные вершины
+	if _, ok := s.outEdges[soruce][target]; !ok {
+		return ErrorEdgeNotFound
+	}
+
 	// Вводим новые параметры
-	s.outEdges[soruce][target] = edge
-	s.inEdges[target][soruce] = edge
+	s.own()
+	s.setOut(soruce, target, edge)
+	s.setIn(soruce, target, edge)
 
 	return nil
 }
 
-func (s *store[K, T]) RemoveEdge(source, target K) error {
+func (s *MemoryStore[K, T]) RemoveEdge(source, target K) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	// Удаляем дугу с обоих концов
-	delete(s.inEdges[target], source)
-	delete(s.outEdges[source], target)
+	if _, ok := s.outEdges[source][target]; !ok {
+		return nil
+	}
+
+	// Удаляем дугу с обоих концов, в мультиграфе вместе с параллельными
+	s.own()
+	s.removePairIn(source, target)
+	s.removePairOut(source, target)
 	return nil
 }
 
-func (s *store[K, T]) Edge(source, target K) (Edge[K], error) {
+func (s *MemoryStore[K, T]) Edge(source, target K) (Edge[K], error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
@@ -199,7 +433,7 @@ func (s *store[K, T]) Edge(source, target K) (Edge[K], error) {
 	return edge, nil
 }
 
-func (s *store[K, T]) ListEdges() ([]Edge[K], error) {
+func (s *MemoryStore[K, T]) ListEdges() ([]Edge[K], error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
@@ -210,5 +444,592 @@ func (s *store[K, T]) ListEdges() ([]Edge[K], error) {
 			res = append(res, edge)
 		}
 	}
+
+	for _, targets := range s.parallelOut {
+		for _, edges := range targets {
+			res = append(res, edges...)
+		}
+	}
+	return res, nil
+}
+
+func (s *MemoryStore[K, T]) InDegree(hash K) (int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if _, ok := s.vertices[hash]; !ok {
+		return 0, ErrorVertextNotFound
+	}
+
+	return len(s.inEdges[hash]) + parallelCount(s.parallelIn[hash]), nil
+}
+
+func (s *MemoryStore[K, T]) OutDegree(hash K) (int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if _, ok := s.vertices[hash]; !ok {
+		return 0, ErrorVertextNotFound
+	}
+
+	return len(s.outEdges[hash]) + parallelCount(s.parallelOut[hash]), nil
+}
+
+func (s *MemoryStore[K, T]) Neighbors(hash K) ([]K, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if _, ok := s.vertices[hash]; !ok {
+		return nil, ErrorVertextNotFound
+	}
+
+	return edgeKeys(s.outEdges[hash]), nil
+}
+
+func (s *MemoryStore[K, T]) Predecessors(hash K) ([]K, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if _, ok := s.vertices[hash]; !ok {
+		return nil, ErrorVertextNotFound
+	}
+
+	return edgeKeys(s.inEdges[hash]), nil
+}
+
+func (s *MemoryStore[K, T]) OutEdges(hash K) ([]Edge[K], error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if _, ok := s.vertices[hash]; !ok {
+		return nil, ErrorVertextNotFound
+	}
+
+	return withParallel(edgeValues(s.outEdges[hash]), s.parallelOut[hash]), nil
+}
+
+func (s *MemoryStore[K, T]) InEdges(hash K) ([]Edge[K], error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if _, ok := s.vertices[hash]; !ok {
+		return nil, ErrorVertextNotFound
+	}
+
+	return withParallel(edgeValues(s.inEdges[hash]), s.parallelIn[hash]), nil
+}
+
+// Ключи карты дуг одной вершины
+func edgeKeys[K comparable](edges map[K]Edge[K]) []K {
+	res := make([]K, 0, len(edges))
+	for key := range edges {
+		res = append(res, key)
+	}
+
+	return res
+}
+
+// Значения карты дуг одной вершины
+func edgeValues[K comparable](edges map[K]Edge[K]) []Edge[K] {
+	res := make([]Edge[K], 0, len(edges))
+	for _, edge := range edges {
+		res = append(res, edge)
+	}
+
+	return res
+}
+
+// Пустое хранилище того же вида, что и s
+func emptyLike[K comparable, T any](s Store[K, T]) Store[K, T] {
+	if factory, ok := s.(StoreFactory[K, T]); ok {
+		return factory.NewEmpty()
+	}
+
+	return NewMemoryStore[K, T]()
+}
+
+// Дуги мультиграфа
+
+func (s *MemoryStore[K, T]) AddParallelEdge(source, target K, edge Edge[K]) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.hasParallel(source, target, edge.Properties.ID) {
+		return ErrorEdgeExists
+	}
+
+	s.own()
+	s.addOut(source, target, edge)
+	s.addIn(source, target, edge)
+
+	return nil
+}
+
+func (s *MemoryStore[K, T]) EditParallelEdge(source, target K, edge Edge[K]) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.hasParallel(source, target, edge.Properties.ID) {
+		return ErrorEdgeNotFound
+	}
+
+	s.own()
+	s.editOut(source, target, edge)
+	s.editIn(source, target, edge)
+
+	return nil
+}
+
+func (s *MemoryStore[K, T]) RemoveParallelEdge(source, target K, id uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.hasParallel(source, target, id) {
+		return ErrorEdgeNotFound
+	}
+
+	s.own()
+	s.removeOut(source, target, id)
+	s.removeIn(source, target, id)
+
+	return nil
+}
+
+func (s *MemoryStore[K, T]) EdgesBetween(source, target K) ([]Edge[K], error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.between(source, target), nil
+}
+
+func (s *MemoryStore[K, T]) EdgeByID(id uint64) (Edge[K], error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.edgeByID(id)
+}
+
+// Дуга по ID из этого хранилища. Вызывать под s.lock
+func (s *MemoryStore[K, T]) edgeByID(id uint64) (Edge[K], error) {
+	pair, ok := s.edgeIDs[id]
+	if !ok {
+		return Edge[K]{}, ErrorEdgeNotFound
+	}
+
+	for _, edge := range s.between(pair.source, pair.target) {
+		if edge.Properties.ID == id {
+			return edge, nil
+		}
+	}
+
+	return Edge[K]{}, ErrorEdgeNotFound
+}
+
+// Все дуги source -> target, первая впереди
+func (s *MemoryStore[K, T]) between(source, target K) []Edge[K] {
+	edge, ok := s.outEdges[source][target]
+	if !ok {
+		return nil
+	}
+
+	extra := s.parallelOut[source][target]
+	res := make([]Edge[K], 0, 1+len(extra))
+
+	return append(append(res, edge), extra...)
+}
+
+func (s *MemoryStore[K, T]) hasParallel(source, target K, id uint64) bool {
+	for _, edge := range s.between(source, target) {
+		if edge.Properties.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Дальше запись половин дуги. Исходящая половина лежит у source, входящая у target,
+// у ShardedStore это могут быть разные шарды. Вызывать после own под s.lock
+
+// Записывает первую дугу пары поверх старой
+func (s *MemoryStore[K, T]) setOut(source, target K, edge Edge[K]) {
+	s.outFor(source)[target] = edge
+	s.indexEdge(source, target, edge.Properties.ID)
+}
+
+func (s *MemoryStore[K, T]) setIn(source, target K, edge Edge[K]) {
+	s.inFor(target)[source] = edge
+}
+
+// Добавляет дугу к параллельным. Первая дуга пары ложится в обычные карты
+func (s *MemoryStore[K, T]) addOut(source, target K, edge Edge[K]) {
+	if _, ok := s.outEdges[source][target]; !ok {
+		s.setOut(source, target, edge)
+		return
+	}
+
+	s.parallelOut = appendParallel(s.parallelOut, source, target, edge)
+	s.indexEdge(source, target, edge.Properties.ID)
+}
+
+func (s *MemoryStore[K, T]) addIn(source, target K, edge Edge[K]) {
+	if _, ok := s.inEdges[target][source]; !ok {
+		s.setIn(source, target, edge)
+		return
+	}
+
+	s.parallelIn = appendParallel(s.parallelIn, target, source, edge)
+}
+
+func (s *MemoryStore[K, T]) editOut(source, target K, edge Edge[K]) {
+	if s.outEdges[source][target].Properties.ID == edge.Properties.ID {
+		s.outFor(source)[target] = edge
+		return
+	}
+
+	replaceParallel(s.parallelOut[source][target], edge)
+}
+
+func (s *MemoryStore[K, T]) editIn(source, target K, edge Edge[K]) {
+	if s.inEdges[target][source].Properties.ID == edge.Properties.ID {
+		s.inFor(target)[source] = edge
+		return
+	}
+
+	replaceParallel(s.parallelIn[target][source], edge)
+}
+
+// Удаляет одну дугу. Если это первая дуга пары, её место занимает следующая
+func (s *MemoryStore[K, T]) removeOut(source, target K, id uint64) {
+	s.unindexEdge(source, target, id)
+
+	if s.outEdges[source][target].Properties.ID != id {
+		s.parallelOut = dropParallel(s.parallelOut, source, target, id)
+		return
+	}
+
+	extra := s.parallelOut[source][target]
+	if len(extra) == 0 {
+		delete(s.outFor(source), target)
+		return
+	}
+
+	s.outFor(source)[target] = extra[0]
+	s.parallelOut = dropParallel(s.parallelOut, source, target, extra[0].Properties.ID)
+}
+
+func (s *MemoryStore[K, T]) removeIn(source, target K, id uint64) {
+	if s.inEdges[target][source].Properties.ID != id {
+		s.parallelIn = dropParallel(s.parallelIn, target, source, id)
+		return
+	}
+
+	extra := s.parallelIn[target][source]
+	if len(extra) == 0 {
+		delete(s.inFor(target), source)
+		return
+	}
+
+	s.inFor(target)[source] = extra[0]
+	s.parallelIn = dropParallel(s.parallelIn, target, source, extra[0].Properties.ID)
+}
+
+// Удаляет все дуги пары
+func (s *MemoryStore[K, T]) removePairOut(source, target K) {
+	for _, edge := range s.between(source, target) {
+		s.unindexEdge(source, target, edge.Properties.ID)
+	}
+
+	delete(s.outFor(source), target)
+	deleteParallel(s.parallelOut, source, target)
+}
+
+func (s *MemoryStore[K, T]) removePairIn(source, target K) {
+	delete(s.inFor(target), source)
+	deleteParallel(s.parallelIn, target, source)
+}
+
+// У ненаправленного графа обе половины дуги с одним ID, в индексе остаётся первая
+func (s *MemoryStore[K, T]) indexEdge(source, target K, id uint64) {
+	if id == 0 {
+		return
+	}
+
+	if s.edgeIDs == nil {
+		s.edgeIDs = make(map[uint64]tuple[K])
+	}
+
+	if _, ok := s.edgeIDs[id]; !ok {
+		s.edgeIDs[id] = tuple[K]{source: source, target: target}
+	}
+}
+
+func (s *MemoryStore[K, T]) unindexEdge(source, target K, id uint64) {
+	if s.edgeIDs[id] == (tuple[K]{source: source, target: target}) {
+		delete(s.edgeIDs, id)
+	}
+}
+
+func appendParallel[K comparable](parallel map[K]map[K][]Edge[K], hash, other K, edge Edge[K]) map[K]map[K][]Edge[K] {
+	if parallel == nil {
+		parallel = make(map[K]map[K][]Edge[K])
+	}
+
+	inner, ok := parallel[hash]
+	if !ok {
+		inner = make(map[K][]Edge[K])
+		parallel[hash] = inner
+	}
+
+	inner[other] = append(inner[other], edge)
+
+	return parallel
+}
+
+func replaceParallel[K comparable](edges []Edge[K], edge Edge[K]) {
+	for i := range edges {
+		if edges[i].Properties.ID == edge.Properties.ID {
+			edges[i] = edge
+			return
+		}
+	}
+}
+
+func dropParallel[K comparable](parallel map[K]map[K][]Edge[K], hash, other K, id uint64) map[K]map[K][]Edge[K] {
+	edges := parallel[hash][other]
+
+	for i := range edges {
+		if edges[i].Properties.ID != id {
+			continue
+		}
+
+		if len(edges) == 1 {
+			deleteParallel(parallel, hash, other)
+		} else {
+			parallel[hash][other] = append(edges[:i:i], edges[i+1:]...)
+		}
+
+		break
+	}
+
+	return parallel
+}
+
+func deleteParallel[K comparable](parallel map[K]map[K][]Edge[K], hash, other K) {
+	inner, ok := parallel[hash]
+	if !ok {
+		return
+	}
+
+	delete(inner, other)
+	if len(inner) == 0 {
+		delete(parallel, hash)
+	}
+}
+
+// Глубокая копия параллельных дуг, срезы в ней меняются на месте
+func cloneParallel[K comparable](parallel map[K]map[K][]Edge[K]) map[K]map[K][]Edge[K] {
+	if parallel == nil {
+		return nil
+	}
+
+	res := make(map[K]map[K][]Edge[K], len(parallel))
+	for hash, inner := range parallel {
+		copied := make(map[K][]Edge[K], len(inner))
+		for other, edges := range inner {
+			copied[other] = append([]Edge[K](nil), edges...)
+		}
+		res[hash] = copied
+	}
+
+	return res
+}
+
+func parallelCount[K comparable](parallel map[K][]Edge[K]) int {
+	count := 0
+	for _, edges := range parallel {
+		count += len(edges)
+	}
+
+	return count
+}
+
+func withParallel[K comparable](edges []Edge[K], parallel map[K][]Edge[K]) []Edge[K] {
+	for _, extra := range parallel {
+		edges = append(edges, extra...)
+	}
+
+	return edges
+}
+
+// Индексы вершин
+
+// У снимка индекс не создать: его карты общие с живым хранилищем
+func (s *MemoryStore[K, T]) CreateIndex(attribute string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.readOnly {
+		return ErrorGraphReadOnly
+	}
+
+	if _, ok := s.attributeIndex[attribute]; ok {
+		return nil
+	}
+
+	s.own()
+	if s.attributeIndex == nil {
+		s.attributeIndex = make(map[string]map[string]map[K]struct{})
+	}
+
+	index := make(map[string]map[K]struct{})
+	for key, props := range s.vertexProperties {
+		if value, ok := props.Attributes[attribute]; ok {
+			addIndexed(index, value, key)
+		}
+	}
+	s.attributeIndex[attribute] = index
+
+	return nil
+}
+
+func (s *MemoryStore[K, T]) CreateWeightIndex() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.readOnly {
+		return ErrorGraphReadOnly
+	}
+
+	if s.weightIndexed {
+		return nil
+	}
+
+	s.own()
+
+	index := make([]weightEntry[K], 0, len(s.vertexProperties))
+	for key, props := range s.vertexProperties {
+		index = append(index, weightEntry[K]{weight: props.FloatWeight, hash: key})
+	}
+
+	sort.Slice(index, func(i, j int) bool {
+		return index[i].weight < index[j].weight
+	})
+
+	s.weightIndex = index
+	s.weightIndexed = true
+
+	return nil
+}
+
+func (s *MemoryStore[K, T]) VerticesByAttribute(attribute, value string) ([]K, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	index, ok := s.attributeIndex[attribute]
+	if !ok {
+		return nil, ErrorIndexNotFound
+	}
+
+	res := make([]K, 0, len(index[value]))
+	for key := range index[value] {
+		res = append(res, key)
+	}
+
 	return res, nil
 }
+
+func (s *MemoryStore[K, T]) VerticesByWeight(min, max float64) ([]K, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if !s.weightIndexed {
+		return nil, ErrorIndexNotFound
+	}
+
+	res := make([]K, 0)
+	for i := s.weightPosition(min); i < len(s.weightIndex) && s.weightIndex[i].weight <= max; i++ {
+		res = append(res, s.weightIndex[i].hash)
+	}
+
+	return res, nil
+}
+
+// Первая позиция с весом не меньше weight
+func (s *MemoryStore[K, T]) weightPosition(weight float64) int {
+	return sort.Search(len(s.weightIndex), func(i int) bool {
+		return s.weightIndex[i].weight >= weight
+	})
+}
+
+// Добавляет вершину в объявленные индексы. Вызывать под s.lock после own
+func (s *MemoryStore[K, T]) indexVertex(key K, props VertexProperties) {
+	for attribute, index := range s.attributeIndex {
+		if value, ok := props.Attributes[attribute]; ok {
+			addIndexed(index, value, key)
+		}
+	}
+
+	if s.weightIndexed {
+		entry := weightEntry[K]{weight: props.FloatWeight, hash: key}
+		s.weightIndex = slices.Insert(s.weightIndex, s.weightPosition(props.FloatWeight), entry)
+	}
+}
+
+// Убирает вершину со старыми свойствами props из индексов
+func (s *MemoryStore[K, T]) unindexVertex(key K, props VertexProperties) {
+	for attribute, index := range s.attributeIndex {
+		if value, ok := props.Attributes[attribute]; ok {
+			removeIndexed(index, value, key)
+		}
+	}
+
+	if s.weightIndexed {
+		for i := s.weightPosition(props.FloatWeight); i < len(s.weightIndex) && s.weightIndex[i].weight == props.FloatWeight; i++ {
+			if s.weightIndex[i].hash == key {
+				s.weightIndex = slices.Delete(s.weightIndex, i, i+1)
+				break
+			}
+		}
+	}
+}
+
+type weightEntry[K comparable] struct {
+	weight float64
+	hash   K
+}
+
+func addIndexed[K comparable](index map[string]map[K]struct{}, value string, key K) {
+	keys, ok := index[value]
+	if !ok {
+		keys = make(map[K]struct{})
+		index[value] = keys
+	}
+
+	keys[key] = struct{}{}
+}
+
+func removeIndexed[K comparable](index map[string]map[K]struct{}, value string, key K) {
+	delete(index[value], key)
+	if len(index[value]) == 0 {
+		delete(index, value)
+	}
+}
+
+// Глубокая копия индексов атрибутов
+func cloneAttributeIndex[K comparable](indexes map[string]map[string]map[K]struct{}) map[string]map[string]map[K]struct{} {
+	if indexes == nil {
+		return nil
+	}
+
+	res := make(map[string]map[string]map[K]struct{}, len(indexes))
+	for attribute, index := range indexes {
+		copied := make(map[string]map[K]struct{}, len(index))
+		for value, keys := range index {
+			copied[value] = maps.Clone(keys)
+		}
+		res[attribute] = copied
+	}
+
+	return res
+}
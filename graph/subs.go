@@ -1,5 +1,10 @@
 package graph
 
+import (
+	"fmt"
+	"hash/fnv"
+)
+
 // Фапйл с дополнительными функциями которые не подошли логически
 
 // Копирует все доп. атрибуты
@@ -9,5 +14,78 @@ func copyVertexProperties(source VertexProperties) func(*VertexProperties) {
 			p.Attributes[k] = v
 		}
 		p.Weight = source.Weight
+		p.FloatWeight = source.FloatWeight
+		p.Labels = source.Labels
+	}
+}
+
+// Копия карты атрибутов
+func copyAttributes(attributes map[string]string) map[string]string {
+	res := make(map[string]string, len(attributes))
+	for k, v := range attributes {
+		res[k] = v
+	}
+
+	return res
+}
+
+// Считает дуги по полустепеням исхода, не собирая список всех дуг
+func edgeCount[K comparable, T any](s Store[K, T]) (int, error) {
+	count := 0
+
+	// Вершины и их степени читаем согласованно
+	err := runView(s, func(view Store[K, T]) error {
+		vertices, err := view.ListVertices()
+		if err != nil {
+			return err
+		}
+
+		for _, vertex := range vertices {
+			degree, err := view.OutDegree(vertex)
+			if err != nil {
+				return err
+			}
+			count += degree
+		}
+
+		return nil
+	})
+
+	return count, err
+}
+
+// Хэш ключа для шардов и префиксных деревьев.
+// Частые типы ключей хэшируются без выделения памяти, остальные через строковое представление
+func keyHash[K comparable](key K) uint64 {
+	switch k := any(key).(type) {
+	case int:
+		return mixHash(uint64(k))
+	case int64:
+		return mixHash(uint64(k))
+	case int32:
+		return mixHash(uint64(k))
+	case uint:
+		return mixHash(uint64(k))
+	case uint64:
+		return mixHash(k)
+	case uint32:
+		return mixHash(uint64(k))
+	case string:
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(k))
+		return h.Sum64()
 	}
+
+	h := fnv.New64a()
+	_, _ = fmt.Fprint(h, key)
+	return h.Sum64()
+}
+
+// Перемешивает биты, чтобы подряд идущие числа расходились по разным шардам и веткам
+func mixHash(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+
+	return x
 }
@@ -3,7 +3,19 @@ package graph
 type Traits struct {
 	IsDirected bool
 	IsWeighted bool
-	IsRooted   bool
+	// Алгоритмы и draw.DOT берут вес из FloatWeight вместо Weight
+	IsFloatWeighted bool
+	IsRooted        bool
+	// Граф обязан оставаться деревом, AddEdge отклоняет лишние дуги
+	IsTree bool
+	// Между парой вершин может быть несколько дуг, каждая со своим ID
+	IsMultigraph bool
+
+	// Корень графа. Хранится как any, потому что Traits не знает тип ключа K.
+	// Заполняется через RootedAt или первой добавленной вершиной при Rooted.
+	// Поле закрыто: корень меняет только граф под блокировкой хранилища,
+	// снаружи он читается через Root
+	root any
 }
 
 // Фнукция указания что граф направленный
@@ -20,9 +32,114 @@ func Weighted() func(*Traits) {
 	}
 }
 
+// Взвешенный граф с дробными весами. Главным становится поле FloatWeight,
+// Weight граф заполняет его целой частью. Опции, пишущие только Weight, на вес не влияют
+func FloatWeighted() func(*Traits) {
+	return func(t *Traits) {
+		t.IsWeighted = true
+		t.IsFloatWeighted = true
+	}
+}
+
+// Мультиграф: AddEdge не отклоняет вторую дугу между теми же вершинами.
+// Хранилище должно реализовывать MultiEdgeStore, как MemoryStore, ShardedStore и FileStore
+func Multigraph() func(*Traits) {
+	return func(t *Traits) {
+		t.IsMultigraph = true
+	}
+}
+
 // Корневой граф
+// Корнем становится первая добавленная вершина.
+// Форму дерева Rooted не проверяет: корневыми бывают и графы потока управления
+// с циклами для DominatorTree. Дерево с проверкой задаёт RootedTree
 func Rooted() func(*Traits) {
 	return func(t *Traits) {
 		t.IsRooted = true
 	}
 }
+
+// Корневой граф с заранее заданным корнем
+func RootedAt[K comparable](root K) func(*Traits) {
+	return func(t *Traits) {
+		t.IsRooted = true
+		t.root = root
+	}
+}
+
+// Корневое дерево: Rooted и вдобавок проверка формы дерева на каждой дуге.
+// Дерево растёт от корня. AddEdge подвешивает вершину без родителя (в ненаправленном
+// графе без рёбер) к корню или к вершине, уже висящей в дереве, и только так.
+// RemoveEdge и RemoveVertexCascade снимают только дуги к листьям.
+// Остальное отклоняется с ErrorEdgeBreaksTree. Проверка смотрит только на степени концов дуги
+func RootedTree() func(*Traits) {
+	return func(t *Traits) {
+		t.IsRooted = true
+		t.IsTree = true
+	}
+}
+
+// Запоминает корень, если граф корневой и корень ещё не задан
+func (t *Traits) recordRoot(hash any) {
+	if t.IsRooted && t.root == nil {
+		t.root = hash
+	}
+}
+
+// Корень пишут AddVertex и RemoveVertex под блокировкой хранилища,
+// поэтому и читать его нужно под ней
+func currentRoot[K comparable, T any](g Graph[K, T]) any {
+	inner, ok := g.(storeGraph[K, T])
+	if !ok || inner.storage() == nil {
+		return g.Traits().root
+	}
+
+	var root any
+	_ = runView(inner.storage(), func(_ Store[K, T]) error {
+		root = g.Traits().root
+		return nil
+	})
+
+	return root
+}
+
+// Забывает корень, если удаляется именно он
+func (t *Traits) forgetRoot(hash any) {
+	if t.IsRooted && t.root == hash {
+		t.root = nil
+	}
+}
+
+// Возвращает корень графа
+func Root[K comparable, T any](g Graph[K, T]) (K, error) {
+	var root K
+
+	if !g.Traits().IsRooted {
+		return root, ErrorNotRooted
+	}
+
+	root, ok := currentRoot(g).(K)
+	if !ok {
+		return root, ErrorNotRooted
+	}
+
+	return root, nil
+}
+
+// Выравнивает Weight и FloatWeight вершины после опций по главному полю
+func (t *Traits) syncVertexWeight(p *VertexProperties) {
+	if t.IsFloatWeighted {
+		p.Weight = int(p.FloatWeight)
+	} else {
+		p.FloatWeight = float64(p.Weight)
+	}
+}
+
+// Выравнивает Weight и FloatWeight дуги после опций по главному полю
+func (t *Traits) syncEdgeWeight(p *EdgeProperties) {
+	if t.IsFloatWeighted {
+		p.Weight = int(p.FloatWeight)
+	} else {
+		p.FloatWeight = float64(p.Weight)
+	}
+}
@@ -0,0 +1,47 @@
+package graph
+
+import "slices"
+
+// Настройки обхода BFS и DFS
+type TraversalOptions struct {
+	// Типы дуг, по которым идёт обход. Пусто значит по всем
+	EdgeTypes []string
+}
+
+// Обход только по дугам данных типов, например FollowEdgeTypes("OWNS", "FOLLOWS")
+func FollowEdgeTypes(types ...string) func(*TraversalOptions) {
+	return func(o *TraversalOptions) {
+		o.EdgeTypes = append(o.EdgeTypes, types...)
+	}
+}
+
+func traversalOptions(options []func(*TraversalOptions)) TraversalOptions {
+	opts := TraversalOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return opts
+}
+
+// Соседи, в которые обход может перейти из hash.
+// Без фильтра это Neighbors, с фильтром соседи по исходящим дугам нужных типов
+func traversalNeighbors[K comparable, T any](g Graph[K, T], hash K, opts TraversalOptions) ([]K, error) {
+	if len(opts.EdgeTypes) == 0 {
+		return g.Neighbors(hash)
+	}
+
+	edges, err := g.OutEdges(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	neighbors := make([]K, 0, len(edges))
+	for _, edge := range edges {
+		if slices.Contains(opts.EdgeTypes, edge.Properties.Type) {
+			neighbors = append(neighbors, edge.Target)
+		}
+	}
+
+	return neighbors, nil
+}
@@ -0,0 +1,193 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// Хранилища, на которых проверяются доступ к соседям и обходы
+var accessorStores = []struct {
+	name string
+	new  func(t *testing.T) Store[int, int]
+}{
+	{"memory", func(*testing.T) Store[int, int] { return NewMemoryStore[int, int]() }},
+	{"sharded", func(*testing.T) Store[int, int] { return NewShardedStore[int, int](4) }},
+	{"dense", func(*testing.T) Store[int, int] { return NewDenseIntStore[int]() }},
+	{"file", func(t *testing.T) Store[int, int] {
+		s := openFileStore(t, t.TempDir(), SyncNone())
+		t.Cleanup(func() {
+			_ = s.Close()
+		})
+		return s
+	}},
+}
+
+// Дуги 1 -> 2, 1 -> 3, 2 -> 3, вершина 4 без дуг
+func accessorGraph(t *testing.T, store Store[int, int], options ...func(*Traits)) Graph[int, int] {
+	t.Helper()
+
+	g := NewWithStore(IntHash, store, options...)
+	for i := 1; i <= 4; i++ {
+		if err := g.AddVertex(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, edge := range [][2]int{{1, 2}, {1, 3}, {2, 3}} {
+		if err := g.AddEdge(edge[0], edge[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return g
+}
+
+func sortedInts(values []int, err error) ([]int, error) {
+	slices.Sort(values)
+	return values, err
+}
+
+// Концы дуг: для OutEdges цели, для InEdges источники
+func edgeEnds(edges []Edge[int], err error, target bool) ([]int, error) {
+	res := make([]int, 0, len(edges))
+	for _, edge := range edges {
+		if target {
+			res = append(res, edge.Target)
+		} else {
+			res = append(res, edge.Source)
+		}
+	}
+
+	return sortedInts(res, err)
+}
+
+func TestVertexAccessors(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []func(*Traits)
+		// Ожидания для вершин 1..4
+		neighbors, predecessors [][]int
+	}{
+		{
+			name:         "directed",
+			options:      []func(*Traits){Directed()},
+			neighbors:    [][]int{{2, 3}, {3}, {}, {}},
+			predecessors: [][]int{{}, {1}, {1, 2}, {}},
+		},
+		{
+			name:         "undirected",
+			neighbors:    [][]int{{2, 3}, {1, 3}, {1, 2}, {}},
+			predecessors: [][]int{{2, 3}, {1, 3}, {1, 2}, {}},
+		},
+	}
+
+	for _, s := range accessorStores {
+		for _, test := range tests {
+			t.Run(s.name+"/"+test.name, func(t *testing.T) {
+				g := accessorGraph(t, s.new(t), test.options...)
+
+				for v := 1; v <= 4; v++ {
+					checks := []struct {
+						name string
+						get  func() ([]int, error)
+						want []int
+					}{
+						{"Neighbors", func() ([]int, error) { return sortedInts(g.Neighbors(v)) }, test.neighbors[v-1]},
+						{"Predecessors", func() ([]int, error) { return sortedInts(g.Predecessors(v)) }, test.predecessors[v-1]},
+						{"OutEdges", func() ([]int, error) {
+							edges, err := g.OutEdges(v)
+							for _, edge := range edges {
+								if edge.Source != v {
+									t.Fatalf("OutEdges(%d) вернул дугу %d -> %d", v, edge.Source, edge.Target)
+								}
+							}
+							return edgeEnds(edges, err, true)
+						}, test.neighbors[v-1]},
+						{"InEdges", func() ([]int, error) {
+							edges, err := g.InEdges(v)
+							for _, edge := range edges {
+								if edge.Target != v {
+									t.Fatalf("InEdges(%d) вернул дугу %d -> %d", v, edge.Source, edge.Target)
+								}
+							}
+							return edgeEnds(edges, err, false)
+						}, test.predecessors[v-1]},
+					}
+
+					for _, check := range checks {
+						got, err := check.get()
+						if err != nil {
+							t.Fatalf("%s(%d): %v", check.name, v, err)
+						}
+						if !slices.Equal(got, check.want) {
+							t.Fatalf("%s(%d) = %v, ожидалось %v", check.name, v, got, check.want)
+						}
+					}
+				}
+
+				if _, err := g.Neighbors(100); !errors.Is(err, ErrorVertextNotFound) {
+					t.Fatalf("Neighbors(100), ошибка %v", err)
+				}
+				if _, err := g.Predecessors(100); !errors.Is(err, ErrorVertextNotFound) {
+					t.Fatalf("Predecessors(100), ошибка %v", err)
+				}
+				if _, err := g.OutEdges(100); !errors.Is(err, ErrorVertextNotFound) {
+					t.Fatalf("OutEdges(100), ошибка %v", err)
+				}
+				if _, err := g.InEdges(100); !errors.Is(err, ErrorVertextNotFound) {
+					t.Fatalf("InEdges(100), ошибка %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestTraversalMissingStart(t *testing.T) {
+	g := accessorGraph(t, NewMemoryStore[int, int](), Directed())
+
+	visited := false
+	visit := func(int) bool {
+		visited = true
+		return false
+	}
+
+	if err := BFS(g, 100, visit); err != nil || visited {
+		t.Fatalf("BFS без начальной вершины: %v, посещено %v", err, visited)
+	}
+	if err := DFS(g, 100, visit); err != nil || visited {
+		t.Fatalf("DFS без начальной вершины: %v, посещено %v", err, visited)
+	}
+}
+
+func TestTraversals(t *testing.T) {
+	for _, s := range accessorStores {
+		t.Run(s.name, func(t *testing.T) {
+			g := accessorGraph(t, s.new(t), Directed())
+
+			var bfs []int
+			err := BFSWithDepth(g, 1, func(v, _ int) bool {
+				bfs = append(bfs, v)
+				return false
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Соседи 1 идут раньше остальных, 4 недостижима
+			if len(bfs) != 3 || bfs[0] != 1 || !slices.Equal(sortedCopy(bfs), []int{1, 2, 3}) {
+				t.Fatalf("BFS = %v", bfs)
+			}
+
+			var dfs []int
+			if err := DFS(g, 2, func(v int) bool {
+				dfs = append(dfs, v)
+				return false
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(dfs, []int{2, 3}) {
+				t.Fatalf("DFS = %v", dfs)
+			}
+		})
+	}
+}
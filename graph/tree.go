@@ -0,0 +1,316 @@
+package graph
+
+import "math/bits"
+
+// Представление корневого дерева поверх графа.
+// Строится один раз, после изменений графа нужно построить заново.
+// Запросы LCA отвечаются двоичным подъёмом за O(log n) после подготовки за O(n log n)
+type Tree[K comparable] struct {
+	// Вершины пронумерованы в порядке прямого обхода
+	vertices []K
+	index    map[K]int
+
+	parent   []int
+	children [][]int
+	depth    []int
+
+	// Порядок выхода из вершины. Поддерево v это номера [v, out[v]]
+	out       []int
+	postOrder []int
+	levels    []int
+
+	// up[j][v] это предок v на 2^j уровней выше
+	up [][]int
+}
+
+// Строит дерево по корневому графу. Ошибка, если граф не дерево
+func NewTree[K comparable, T any](g Graph[K, T]) (*Tree[K], error) {
+	root, err := Root(g)
+	if err != nil {
+		return nil, err
+	}
+
+	adjacencyMap, err := g.AdjacencyMap()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := adjacencyMap[root]; !ok {
+		return nil, ErrorVertextNotFound
+	}
+
+	t := &Tree[K]{
+		index: make(map[K]int, len(adjacencyMap)),
+	}
+
+	type frame struct {
+		vertex K
+		parent int
+	}
+
+	// Прямой обход. Для ненаправленного графа не возвращаемся в родителя
+	stack := []frame{{vertex: root, parent: -1}}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if _, ok := t.index[current.vertex]; ok {
+			return nil, ErrorNotTree
+		}
+
+		i := len(t.vertices)
+		t.index[current.vertex] = i
+		t.vertices = append(t.vertices, current.vertex)
+		t.parent = append(t.parent, current.parent)
+		t.children = append(t.children, nil)
+
+		depth := 0
+		if current.parent >= 0 {
+			depth = t.depth[current.parent] + 1
+			t.children[current.parent] = append(t.children[current.parent], i)
+		}
+		t.depth = append(t.depth, depth)
+
+		for adjacency := range adjacencyMap[current.vertex] {
+			if !g.Traits().IsDirected && current.parent >= 0 && adjacency == t.vertices[current.parent] {
+				continue
+			}
+			stack = append(stack, frame{vertex: adjacency, parent: i})
+		}
+	}
+
+	// Все вершины должны быть достижимы из корня
+	if len(t.vertices) != len(adjacencyMap) {
+		return nil, ErrorNotTree
+	}
+
+	t.prepare()
+
+	return t, nil
+}
+
+// Считает пост-порядок, уровни и таблицу двоичного подъёма
+func (t *Tree[K]) prepare() {
+	n := len(t.vertices)
+
+	// Номера идут в прямом порядке, поэтому последний потомок имеет наибольший номер
+	t.out = make([]int, n)
+	for v := n - 1; v >= 0; v-- {
+		t.out[v] = v
+		for _, child := range t.children[v] {
+			if t.out[child] > t.out[v] {
+				t.out[v] = t.out[child]
+			}
+		}
+	}
+
+	t.postOrder = make([]int, 0, n)
+	type frame struct {
+		vertex, next int
+	}
+	stack := []frame{{vertex: 0}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next < len(t.children[top.vertex]) {
+			child := t.children[top.vertex][top.next]
+			top.next++
+			stack = append(stack, frame{vertex: child})
+			continue
+		}
+		t.postOrder = append(t.postOrder, top.vertex)
+		stack = stack[:len(stack)-1]
+	}
+
+	t.levels = make([]int, 0, n)
+	t.levels = append(t.levels, 0)
+	for i := 0; i < len(t.levels); i++ {
+		t.levels = append(t.levels, t.children[t.levels[i]]...)
+	}
+
+	log := bits.Len(uint(n))
+	t.up = make([][]int, log+1)
+	t.up[0] = make([]int, n)
+	for v := 0; v < n; v++ {
+		t.up[0][v] = t.parent[v]
+		if t.up[0][v] < 0 {
+			t.up[0][v] = v
+		}
+	}
+
+	for j := 1; j <= log; j++ {
+		t.up[j] = make([]int, n)
+		for v := 0; v < n; v++ {
+			t.up[j][v] = t.up[j-1][t.up[j-1][v]]
+		}
+	}
+}
+
+// Корень дерева
+func (t *Tree[K]) Root() K {
+	return t.vertices[0]
+}
+
+// Родитель вершины. false для корня
+func (t *Tree[K]) Parent(hash K) (K, bool, error) {
+	var empty K
+
+	i, ok := t.index[hash]
+	if !ok {
+		return empty, false, ErrorVertextNotFound
+	}
+
+	if t.parent[i] < 0 {
+		return empty, false, nil
+	}
+
+	return t.vertices[t.parent[i]], true, nil
+}
+
+// Дети вершины
+func (t *Tree[K]) Children(hash K) ([]K, error) {
+	i, ok := t.index[hash]
+	if !ok {
+		return nil, ErrorVertextNotFound
+	}
+
+	return t.keys(t.children[i]), nil
+}
+
+// Глубина вершины, у корня 0
+func (t *Tree[K]) Depth(hash K) (int, error) {
+	i, ok := t.index[hash]
+	if !ok {
+		return 0, ErrorVertextNotFound
+	}
+
+	return t.depth[i], nil
+}
+
+// Все вершины поддерева в прямом порядке, включая саму вершину
+func (t *Tree[K]) Subtree(hash K) ([]K, error) {
+	i, ok := t.index[hash]
+	if !ok {
+		return nil, ErrorVertextNotFound
+	}
+
+	res := make([]K, 0, t.out[i]-i+1)
+	res = append(res, t.vertices[i:t.out[i]+1]...)
+
+	return res, nil
+}
+
+// Проверяет, является ли a предком b. Вершина считается предком самой себя
+func (t *Tree[K]) IsAncestor(a, b K) (bool, error) {
+	i, ok := t.index[a]
+	if !ok {
+		return false, ErrorVertextNotFound
+	}
+
+	j, ok := t.index[b]
+	if !ok {
+		return false, ErrorVertextNotFound
+	}
+
+	return t.isAncestor(i, j), nil
+}
+
+func (t *Tree[K]) isAncestor(i, j int) bool {
+	return i <= j && j <= t.out[i]
+}
+
+// Прямой обход. Останавливается, если visit вернёт true
+func (t *Tree[K]) PreOrder(visit func(K) bool) {
+	for _, vertex := range t.vertices {
+		if visit(vertex) {
+			return
+		}
+	}
+}
+
+// Обратный обход, дети раньше родителя
+func (t *Tree[K]) PostOrder(visit func(K) bool) {
+	for _, i := range t.postOrder {
+		if visit(t.vertices[i]) {
+			return
+		}
+	}
+}
+
+// Обход по уровням, вместе с глубиной
+func (t *Tree[K]) LevelOrder(visit func(K, int) bool) {
+	for _, i := range t.levels {
+		if visit(t.vertices[i], t.depth[i]) {
+			return
+		}
+	}
+}
+
+// Эйлеров обход: вершина записывается при входе и после возврата из каждого ребёнка.
+// Длина 2n-1
+func (t *Tree[K]) EulerTour() []K {
+	res := make([]K, 0, 2*len(t.vertices)-1)
+
+	type frame struct {
+		vertex, next int
+	}
+	stack := []frame{{vertex: 0}}
+	res = append(res, t.vertices[0])
+
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next < len(t.children[top.vertex]) {
+			child := t.children[top.vertex][top.next]
+			top.next++
+			stack = append(stack, frame{vertex: child})
+			res = append(res, t.vertices[child])
+			continue
+		}
+
+		stack = stack[:len(stack)-1]
+		if len(stack) > 0 {
+			res = append(res, t.vertices[stack[len(stack)-1].vertex])
+		}
+	}
+
+	return res
+}
+
+// Наименьший общий предок двух вершин
+func (t *Tree[K]) LCA(a, b K) (K, error) {
+	var empty K
+
+	i, ok := t.index[a]
+	if !ok {
+		return empty, ErrorVertextNotFound
+	}
+
+	j, ok := t.index[b]
+	if !ok {
+		return empty, ErrorVertextNotFound
+	}
+
+	if t.isAncestor(i, j) {
+		return a, nil
+	}
+	if t.isAncestor(j, i) {
+		return b, nil
+	}
+
+	// Поднимаем i, пока он не станет предком j
+	for k := len(t.up) - 1; k >= 0; k-- {
+		if !t.isAncestor(t.up[k][i], j) {
+			i = t.up[k][i]
+		}
+	}
+
+	return t.vertices[t.up[0][i]], nil
+}
+
+func (t *Tree[K]) keys(indexes []int) []K {
+	res := make([]K, 0, len(indexes))
+	for _, i := range indexes {
+		res = append(res, t.vertices[i])
+	}
+
+	return res
+}
@@ -0,0 +1,351 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// Дерево с корнем 1:
+//
+//	1 -> 2, 1 -> 3, 2 -> 4, 2 -> 5, 3 -> 6, 5 -> 7
+var (
+	treeEdges   = [][2]int{{1, 2}, {1, 3}, {2, 4}, {2, 5}, {3, 6}, {5, 7}}
+	treeParents = map[int]int{2: 1, 3: 1, 4: 2, 5: 2, 6: 3, 7: 5}
+	treeDepths  = map[int]int{1: 0, 2: 1, 3: 1, 4: 2, 5: 2, 6: 2, 7: 3}
+)
+
+func knownTree(t *testing.T, options ...func(*Traits)) *Tree[int] {
+	t.Helper()
+
+	g := New(IntHash, append(options, RootedTree())...)
+	for i := 1; i <= 7; i++ {
+		if err := g.AddVertex(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, edge := range treeEdges {
+		if err := g.AddEdge(edge[0], edge[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tree, err := NewTree(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tree
+}
+
+// Порядок детей не задан, поэтому обходы проверяются по свойствам,
+// а не сравнением с одной последовательностью
+func TestTreeTraversals(t *testing.T) {
+	kinds := map[string][]func(*Traits){
+		"directed":   {Directed()},
+		"undirected": nil,
+	}
+
+	for name, options := range kinds {
+		t.Run(name, func(t *testing.T) {
+			tree := knownTree(t, options...)
+
+			if tree.Root() != 1 {
+				t.Fatalf("корень %d", tree.Root())
+			}
+
+			for v := 1; v <= 7; v++ {
+				parent, ok, err := tree.Parent(v)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if want, wantOK := treeParents[v]; parent != want || ok != wantOK {
+					t.Fatalf("Parent(%d) = %d, %v", v, parent, ok)
+				}
+
+				if depth, _ := tree.Depth(v); depth != treeDepths[v] {
+					t.Fatalf("Depth(%d) = %d, ожидалось %d", v, depth, treeDepths[v])
+				}
+			}
+
+			var pre []int
+			tree.PreOrder(func(v int) bool {
+				pre = append(pre, v)
+				return false
+			})
+			checkTreeOrder(t, tree, "PreOrder", pre, true)
+
+			var post []int
+			tree.PostOrder(func(v int) bool {
+				post = append(post, v)
+				return false
+			})
+			checkTreeOrder(t, tree, "PostOrder", post, false)
+
+			var levels []int
+			tree.LevelOrder(func(v, depth int) bool {
+				if depth != treeDepths[v] {
+					t.Fatalf("LevelOrder дал %d глубину %d", v, depth)
+				}
+				if len(levels) > 0 && depth < treeDepths[levels[len(levels)-1]] {
+					t.Fatalf("LevelOrder вернулся на уровень выше: %v, %d", levels, v)
+				}
+				levels = append(levels, v)
+				return false
+			})
+			if sorted := sortedCopy(levels); !slices.Equal(sorted, []int{1, 2, 3, 4, 5, 6, 7}) {
+				t.Fatalf("LevelOrder = %v", levels)
+			}
+
+			// Обход останавливается на первом true
+			var visited []int
+			tree.PreOrder(func(v int) bool {
+				visited = append(visited, v)
+				return len(visited) == 3
+			})
+			if len(visited) != 3 {
+				t.Fatalf("PreOrder не остановился: %v", visited)
+			}
+		})
+	}
+}
+
+// Каждая вершина ровно один раз, поддерево лежит подряд:
+// в прямом порядке начинается с вершины, в обратном ей заканчивается
+func checkTreeOrder(t *testing.T, tree *Tree[int], name string, order []int, pre bool) {
+	t.Helper()
+
+	if sorted := sortedCopy(order); !slices.Equal(sorted, []int{1, 2, 3, 4, 5, 6, 7}) {
+		t.Fatalf("%s = %v", name, order)
+	}
+
+	for v := 1; v <= 7; v++ {
+		subtree, err := tree.Subtree(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		at := slices.Index(order, v)
+		start := at
+		if !pre {
+			start = at - len(subtree) + 1
+		}
+		if start < 0 {
+			t.Fatalf("%s = %v: поддерево %d не помещается перед ним", name, order, v)
+		}
+
+		block := sortedCopy(order[start : start+len(subtree)])
+		if !slices.Equal(block, sortedCopy(subtree)) {
+			t.Fatalf("%s = %v: поддерево %d = %v лежит не подряд", name, order, v, subtree)
+		}
+	}
+}
+
+func TestTreeEulerTour(t *testing.T) {
+	tree := knownTree(t, Directed())
+	tour := tree.EulerTour()
+
+	if len(tour) != 2*7-1 || tour[0] != 1 || tour[len(tour)-1] != 1 {
+		t.Fatalf("EulerTour = %v", tour)
+	}
+
+	// Соседние вершины обхода связаны ребром, и каждое ребро пройдено вниз и вверх
+	down := make(map[[2]int]int)
+	up := make(map[[2]int]int)
+	for i := 1; i < len(tour); i++ {
+		a, b := tour[i-1], tour[i]
+		switch {
+		case treeParents[b] == a:
+			down[[2]int{a, b}]++
+		case treeParents[a] == b:
+			up[[2]int{b, a}]++
+		default:
+			t.Fatalf("EulerTour = %v: %d и %d не соседи", tour, a, b)
+		}
+	}
+
+	for _, edge := range treeEdges {
+		if down[edge] != 1 || up[edge] != 1 {
+			t.Fatalf("EulerTour = %v: ребро %v пройдено %d вниз и %d вверх", tour, edge, down[edge], up[edge])
+		}
+	}
+}
+
+func TestTreeLCA(t *testing.T) {
+	tree := knownTree(t, Directed())
+
+	tests := []struct {
+		a, b, want int
+	}{
+		{4, 5, 2},
+		{4, 7, 2},
+		{7, 4, 2},
+		{7, 6, 1},
+		{5, 7, 5},
+		{7, 5, 5},
+		{6, 3, 3},
+		{4, 4, 4},
+		{1, 7, 1},
+		{2, 3, 1},
+	}
+
+	for _, test := range tests {
+		got, err := tree.LCA(test.a, test.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Fatalf("LCA(%d, %d) = %d, ожидалось %d", test.a, test.b, got, test.want)
+		}
+
+		ancestor, _ := tree.IsAncestor(test.want, test.a)
+		if !ancestor {
+			t.Fatalf("IsAncestor(%d, %d) = false", test.want, test.a)
+		}
+	}
+
+	if _, err := tree.LCA(1, 100); !errors.Is(err, ErrorVertextNotFound) {
+		t.Fatalf("LCA с чужой вершиной, ошибка %v", err)
+	}
+}
+
+func TestNewTreeNotTree(t *testing.T) {
+	// Rooted без проверки формы позволяет цикл, NewTree его отклоняет
+	g := New(IntHash, Directed(), Rooted())
+	for i := 1; i <= 3; i++ {
+		_ = g.AddVertex(i)
+	}
+	_ = g.AddEdge(1, 2)
+	_ = g.AddEdge(2, 3)
+	_ = g.AddEdge(3, 2)
+
+	if _, err := NewTree(g); !errors.Is(err, ErrorNotTree) {
+		t.Fatalf("NewTree с циклом, ошибка %v", err)
+	}
+
+	// Вершина вне дерева
+	_ = g.RemoveEdge(3, 2)
+	_ = g.AddVertex(4)
+	if _, err := NewTree(g); !errors.Is(err, ErrorNotTree) {
+		t.Fatalf("NewTree с недостижимой вершиной, ошибка %v", err)
+	}
+}
+
+// Шаги по порядку над деревом из вершин 1..5 с корнем 1
+func TestRootedTreeEdges(t *testing.T) {
+	type step struct {
+		remove       bool
+		source, dest int
+		err          error
+	}
+
+	tests := []struct {
+		name    string
+		options []func(*Traits)
+		steps   []step
+	}{
+		{"directed", []func(*Traits){Directed()}, []step{
+			// Вершина 2 ещё не висит в дереве
+			{false, 2, 3, ErrorEdgeBreaksTree},
+			{false, 1, 2, nil},
+			{false, 2, 1, ErrorEdgeBreaksTree},
+			{false, 2, 3, nil},
+			// У 2 уже есть родитель
+			{false, 3, 2, ErrorEdgeBreaksTree},
+			{false, 3, 3, ErrorEdgeBreaksTree},
+			// Дугу 1 -> 2 снять нельзя, под 2 висит 3
+			{true, 1, 2, ErrorEdgeBreaksTree},
+			{true, 2, 3, nil},
+			// Оторванная 3 не может стать родителем
+			{false, 3, 4, ErrorEdgeBreaksTree},
+			{false, 1, 3, nil},
+			{false, 3, 4, nil},
+			{false, 4, 5, nil},
+		}},
+		{"undirected", nil, []step{
+			{false, 2, 3, ErrorEdgeBreaksTree},
+			// Порядок концов ребра не важен
+			{false, 2, 1, nil},
+			{false, 2, 3, nil},
+			// Оба конца уже в дереве
+			{false, 1, 3, ErrorEdgeBreaksTree},
+			{false, 3, 3, ErrorEdgeBreaksTree},
+			{true, 1, 2, ErrorEdgeBreaksTree},
+			{true, 3, 2, nil},
+			{false, 3, 4, ErrorEdgeBreaksTree},
+			{false, 4, 2, nil},
+			{false, 4, 5, nil},
+			// Корень с одним ребром листом не считается
+			{true, 2, 1, ErrorEdgeBreaksTree},
+			{false, 3, 1, nil},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := New(IntHash, append(test.options, RootedTree())...)
+			for i := 1; i <= 5; i++ {
+				_ = g.AddVertex(i)
+			}
+
+			for _, s := range test.steps {
+				var err error
+				if s.remove {
+					err = g.RemoveEdge(s.source, s.dest)
+				} else {
+					err = g.AddEdge(s.source, s.dest)
+				}
+
+				if !errors.Is(err, s.err) {
+					t.Fatalf("шаг %+v вернул %v", s, err)
+				}
+			}
+
+			if _, err := NewTree(g); err != nil {
+				t.Fatalf("после всех шагов не дерево: %v", err)
+			}
+		})
+	}
+}
+
+// Каскадное удаление снимает дуги к листьям, но не отрывает поддерево
+func TestRootedTreeCascade(t *testing.T) {
+	for _, options := range [][]func(*Traits){{Directed(), RootedTree()}, {RootedTree()}} {
+		g := New(IntHash, options...)
+		for i := 1; i <= 4; i++ {
+			_ = g.AddVertex(i)
+		}
+		_ = g.AddEdge(1, 2)
+		_ = g.AddEdge(2, 3)
+		_ = g.AddEdge(2, 4)
+
+		if _, err := g.RemoveVertexCascade(1); !errors.Is(err, ErrorEdgeBreaksTree) {
+			t.Fatalf("каскад корня с поддеревом, ошибка %v", err)
+		}
+		if size, _ := g.Size(); size != 3 {
+			t.Fatalf("после отката осталось %d дуг", size)
+		}
+
+		// У 2 только листья: они отпадают, сама 2 уходит вместе с дугой к корню
+		removed, err := g.RemoveVertexCascade(2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(removed) != 3 {
+			t.Fatalf("снято %d дуг, ожидалось 3", len(removed))
+		}
+
+		// Освободившиеся вершины снова можно подвесить
+		if err := g.AddEdge(1, 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func sortedCopy(values []int) []int {
+	res := slices.Clone(values)
+	slices.Sort(res)
+
+	return res
+}
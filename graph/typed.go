@@ -0,0 +1,176 @@
+package graph
+
+// Граф с данными дуг типа E вместо any.
+// Данные лежат в том же Properties.Data, поэтому сам *Typed остаётся Graph[K, T]:
+// методы Graph не перекрыты, и его можно передать в любой алгоритм пакета.
+// Типизированные методы называются с Typed (AddTypedEdge, TypedEdge, TypedOutEdges...),
+// принимают и отдают E без приведения типов у вызывающего.
+// Дуга без данных читается как нулевое E, данные другого типа, записанные
+// в обход Typed, дают ErrorEdgeDataType
+type Typed[K comparable, T any, E any] struct {
+	Graph[K, T]
+}
+
+// Дуга вместе с данными типа E
+type TypedEdge[V any, E any] struct {
+	Edge[V]
+	Data E
+}
+
+// Оборачивает граф g. Сам граф не копируется
+func NewTyped[K comparable, T any, E any](g Graph[K, T]) *Typed[K, T, E] {
+	return &Typed[K, T, E]{Graph: g}
+}
+
+// Дуга обычного графа с данными типа E, например TypedEdgeOf[Route](edge)
+func TypedEdgeOf[E any, V any](edge Edge[V]) (TypedEdge[V, E], error) {
+	if edge.Properties.Data == nil {
+		var zero E
+		return TypedEdge[V, E]{Edge: edge, Data: zero}, nil
+	}
+
+	data, ok := edge.Properties.Data.(E)
+	if !ok {
+		return TypedEdge[V, E]{}, ErrorEdgeDataType
+	}
+
+	return TypedEdge[V, E]{Edge: edge, Data: data}, nil
+}
+
+func typedEdges[E any, V any](edges []Edge[V]) ([]TypedEdge[V, E], error) {
+	res := make([]TypedEdge[V, E], 0, len(edges))
+	for _, edge := range edges {
+		typed, err := TypedEdgeOf[E](edge)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, typed)
+	}
+
+	return res, nil
+}
+
+func edgeData[E any](data E) func(*EdgeProperties) {
+	return func(p *EdgeProperties) {
+		p.Data = data
+	}
+}
+
+// Добавляет дугу с данными data
+func (g *Typed[K, T, E]) AddTypedEdge(source, target K, data E, options ...func(*EdgeProperties)) error {
+	_, err := g.AddTypedEdgeWithID(source, target, data, options...)
+	return err
+}
+
+// Как AddTypedEdge, но возвращает ID новой дуги
+func (g *Typed[K, T, E]) AddTypedEdgeWithID(source, target K, data E, options ...func(*EdgeProperties)) (uint64, error) {
+	// Данные ставятся последними, чтобы опции их не перетёрли
+	withData := make([]func(*EdgeProperties), 0, len(options)+1)
+	withData = append(withData, options...)
+	withData = append(withData, edgeData(data))
+
+	return g.Graph.AddEdgeWithID(source, target, withData...)
+}
+
+// Дуга между source и target вместе с вершинами, как у Graph.Edge
+func (g *Typed[K, T, E]) TypedEdge(source, target K) (TypedEdge[T, E], error) {
+	edge, err := g.Graph.Edge(source, target)
+	if err != nil {
+		return TypedEdge[T, E]{}, err
+	}
+
+	return TypedEdgeOf[E](edge)
+}
+
+// Только данные дуги между source и target
+func (g *Typed[K, T, E]) EdgeData(source, target K) (E, error) {
+	edge, err := g.TypedEdge(source, target)
+	return edge.Data, err
+}
+
+// Заменяет данные дуги. В мультиграфе у всех дуг пары
+func (g *Typed[K, T, E]) SetEdgeData(source, target K, data E) error {
+	return g.Graph.EditEdge(source, target, edgeData(data))
+}
+
+// Заменяет данные одной дуги мультиграфа
+func (g *Typed[K, T, E]) SetEdgeDataByID(id uint64, data E) error {
+	return g.Graph.EditEdgeByID(id, edgeData(data))
+}
+
+func (g *Typed[K, T, E]) TypedEdges() ([]TypedEdge[K, E], error) {
+	edges, err := g.Graph.Edges()
+	if err != nil {
+		return nil, err
+	}
+
+	return typedEdges[E](edges)
+}
+
+func (g *Typed[K, T, E]) TypedEdgesBetween(source, target K) ([]TypedEdge[K, E], error) {
+	edges, err := g.Graph.EdgesBetween(source, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return typedEdges[E](edges)
+}
+
+func (g *Typed[K, T, E]) TypedEdgeByID(id uint64) (TypedEdge[K, E], error) {
+	edge, err := g.Graph.EdgeByID(id)
+	if err != nil {
+		return TypedEdge[K, E]{}, err
+	}
+
+	return TypedEdgeOf[E](edge)
+}
+
+func (g *Typed[K, T, E]) TypedOutEdges(hash K) ([]TypedEdge[K, E], error) {
+	edges, err := g.Graph.OutEdges(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return typedEdges[E](edges)
+}
+
+func (g *Typed[K, T, E]) TypedInEdges(hash K) ([]TypedEdge[K, E], error) {
+	edges, err := g.Graph.InEdges(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return typedEdges[E](edges)
+}
+
+// Batch, в котором tx тоже типизирован
+func (g *Typed[K, T, E]) TypedBatch(fn func(tx *Typed[K, T, E]) error) error {
+	return g.Graph.Batch(func(tx Graph[K, T]) error {
+		return fn(NewTyped[K, T, E](tx))
+	})
+}
+
+// Вес из данных дуги для взвешенных алгоритмов, как WeightFromData
+func (g *Typed[K, T, E]) WeightBy(weight func(data E) float64) func(*WeightOptions[K]) {
+	return WeightBy(func(edge Edge[K]) (float64, error) {
+		typed, err := TypedEdgeOf[E](edge)
+		if err != nil {
+			return 0, err
+		}
+
+		return weight(typed.Data), nil
+	})
+}
+
+func (g *Typed[K, T, E]) hashFunc() Hash[K, T] {
+	return hashOf(g.Graph)
+}
+
+func (g *Typed[K, T, E]) storage() Store[K, T] {
+	return storeOf(g.Graph)
+}
+
+func (g *Typed[K, T, E]) readView(fn func(view Graph[K, T]) error) error {
+	return readGraph(g.Graph, fn)
+}
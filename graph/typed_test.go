@@ -0,0 +1,187 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+)
+
+type route struct {
+	km   float64
+	road string
+}
+
+// *Typed остаётся обычным графом
+var _ Graph[int, int] = (*Typed[int, int, route])(nil)
+
+func TestTypedEdgeData(t *testing.T) {
+	g := NewTyped[int, int, route](New(IntHash, Directed()))
+	for i := 1; i <= 3; i++ {
+		_ = g.AddVertex(i)
+	}
+
+	if err := g.AddTypedEdge(1, 2, route{km: 12.5, road: "M1"}, EdgeWeight(3)); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := g.EdgeData(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != (route{km: 12.5, road: "M1"}) {
+		t.Fatalf("EdgeData = %+v", data)
+	}
+
+	if err := g.SetEdgeData(1, 2, route{km: 13, road: "M2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	edge, err := g.TypedEdge(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edge.Data != (route{km: 13, road: "M2"}) || edge.Properties.Weight != 3 {
+		t.Fatalf("после SetEdgeData дуга %+v", edge)
+	}
+
+	// Обычный метод Graph видит те же данные в Properties.Data
+	plain, err := g.Edge(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.Properties.Data != (route{km: 13, road: "M2"}) {
+		t.Fatalf("Edge().Properties.Data = %#v", plain.Properties.Data)
+	}
+
+	// Дуга без данных читается как нулевое значение
+	if err := g.AddEdge(2, 3); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := g.EdgeData(2, 3); err != nil || data != (route{}) {
+		t.Fatalf("EdgeData без данных = %+v, %v", data, err)
+	}
+
+	if _, err := g.EdgeData(3, 1); !errors.Is(err, ErrorEdgeNotFound) {
+		t.Fatalf("EdgeData несуществующей дуги, ошибка %v", err)
+	}
+}
+
+// Данные другого типа, записанные через обычный Graph
+func TestTypedEdgeWrongType(t *testing.T) {
+	g := NewTyped[int, int, route](New(IntHash, Directed()))
+	_ = g.AddVertex(1)
+	_ = g.AddVertex(2)
+
+	if err := g.AddEdge(1, 2, func(p *EdgeProperties) {
+		p.Data = "M1"
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.EdgeData(1, 2); !errors.Is(err, ErrorEdgeDataType) {
+		t.Fatalf("EdgeData, ошибка %v", err)
+	}
+	if _, err := g.TypedEdges(); !errors.Is(err, ErrorEdgeDataType) {
+		t.Fatalf("TypedEdges, ошибка %v", err)
+	}
+	if _, err := g.TypedOutEdges(1); !errors.Is(err, ErrorEdgeDataType) {
+		t.Fatalf("TypedOutEdges, ошибка %v", err)
+	}
+
+	// Указатель на тот же тип тоже другой тип
+	if _, err := TypedEdgeOf[route](Edge[int]{Properties: EdgeProperties{Data: &route{}}}); !errors.Is(err, ErrorEdgeDataType) {
+		t.Fatalf("TypedEdgeOf, ошибка %v", err)
+	}
+
+	// SetEdgeData исправляет данные
+	if err := g.SetEdgeData(1, 2, route{road: "M1"}); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := g.EdgeData(1, 2); err != nil || data.road != "M1" {
+		t.Fatalf("EdgeData = %+v, %v", data, err)
+	}
+}
+
+func TestTypedMultigraph(t *testing.T) {
+	g := NewTyped[int, int, route](New(IntHash, Directed(), Multigraph()))
+	_ = g.AddVertex(1)
+	_ = g.AddVertex(2)
+
+	slow, err := g.AddTypedEdgeWithID(1, 2, route{km: 30, road: "old"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fast, err := g.AddTypedEdgeWithID(1, 2, route{km: 20, road: "new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.SetEdgeDataByID(slow, route{km: 25, road: "old"}); err != nil {
+		t.Fatal(err)
+	}
+
+	edge, err := g.TypedEdgeByID(slow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edge.Data.km != 25 {
+		t.Fatalf("TypedEdgeByID = %+v", edge)
+	}
+
+	edges, err := g.TypedEdgesBetween(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	km := map[uint64]float64{}
+	for _, edge := range edges {
+		km[edge.Properties.ID] = edge.Data.km
+	}
+	if len(km) != 2 || km[slow] != 25 || km[fast] != 20 {
+		t.Fatalf("TypedEdgesBetween = %v", km)
+	}
+
+	in, err := g.TypedInEdges(2)
+	if err != nil || len(in) != 2 {
+		t.Fatalf("TypedInEdges = %v, %v", in, err)
+	}
+
+	// Вес из данных: выбирается более короткая дуга
+	_, distance, err := ShortestPath[int, int](g, 1, 2, g.WeightBy(func(data route) float64 {
+		return data.km
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if distance != 20 {
+		t.Fatalf("расстояние %v, ожидалось 20", distance)
+	}
+}
+
+func TestTypedBatch(t *testing.T) {
+	g := NewTyped[int, int, route](New(IntHash, Directed()))
+	_ = g.AddVertex(1)
+	_ = g.AddVertex(2)
+
+	failed := errors.New("откат")
+	err := g.TypedBatch(func(tx *Typed[int, int, route]) error {
+		if err := tx.AddTypedEdge(1, 2, route{road: "M1"}); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("TypedBatch вернул %v", err)
+	}
+	if _, err := g.EdgeData(1, 2); !errors.Is(err, ErrorEdgeNotFound) {
+		t.Fatalf("дуга откатившегося Batch осталась: %v", err)
+	}
+
+	err = g.TypedBatch(func(tx *Typed[int, int, route]) error {
+		return tx.AddTypedEdge(1, 2, route{road: "M1"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := g.EdgeData(1, 2); err != nil || data.road != "M1" {
+		t.Fatalf("EdgeData = %+v, %v", data, err)
+	}
+}
@@ -9,6 +9,15 @@ type undirected[K comparable, T any] struct {
 	hash   Hash[K, T]
 	traits *Traits
 	store  Store[K, T]
+	// Граф внутри Batch, хранилище уже заблокировано
+	batched bool
+
+	events *observers[K]
+	// События текущего Batch, уходят подписчикам после его успеха
+	pending *[]Event[K]
+
+	// ID дуг мультиграфа
+	ids *edgeCounter
 }
 
 // Конструктор создания
@@ -17,6 +26,8 @@ func newUndirected[K comparable, T any](hash Hash[K, T], traits *Traits, store S
 		hash:   hash,
 		traits: traits,
 		store:  store,
+		events: newObservers[K](),
+		ids:    newEdgeCounter(store, traits),
 	}
 }
 
@@ -26,6 +37,10 @@ func (u *undirected[K, T]) Traits() *Traits {
 }
 
 func (u *undirected[K, T]) AddVertex(value T, options ...func(*VertexProperties)) error {
+	if u.hash == nil {
+		return ErrorHashUnknown
+	}
+
 	hash := u.hash(value)
 
 	prop := VertexProperties{
@@ -36,8 +51,37 @@ func (u *undirected[K, T]) AddVertex(value T, options ...func(*VertexProperties)
 	for _, option := range options {
 		option(&prop)
 	}
+	u.traits.syncVertexWeight(&prop)
+
+	// Корень и события обрабатываем под той же блокировкой, что и саму вершину
+	if (u.traits.IsRooted || u.events.active()) && !u.batched {
+		return u.atomic(func(tx *undirected[K, T]) error {
+			return tx.addVertex(hash, value, prop)
+		})
+	}
+
+	return u.addVertex(hash, value, prop)
+}
+
+func (u *undirected[K, T]) addVertex(hash K, value T, prop VertexProperties) error {
+	// Слушатели узнают только об изменении, которое точно применится
+	if u.events.active() {
+		if _, _, err := u.store.Vertex(hash); err == nil {
+			return ErrorVertexExists
+		}
+	}
+
+	event := Event[K]{Kind: VertexAdded, Vertex: hash, VertexProperties: prop}
 
-	return u.store.AddVertex(hash, value, prop)
+	return u.events.change(u.pending, event, func() error {
+		if err := u.store.AddVertex(hash, value, prop); err != nil {
+			return err
+		}
+
+		u.traits.recordRoot(hash)
+
+		return nil
+	})
 }
 
 func (u *undirected[K, T]) Vertex(hash K) (T, error) {
@@ -55,23 +99,116 @@ func (u *undirected[K, T]) VertexWithProperties(hash K) (T, VertexProperties, er
 }
 
 func (u *undirected[K, T]) RemoveVertex(hash K) error {
-	return u.store.RemoveVertex(hash)
-}
-
-func (u *undirected[K, T]) AddEdge(source, target K, options ...func(*EdgeProperties)) error {
-	if _, _, err := u.store.Vertex(source); err != nil {
-		return err
+	if (u.traits.IsRooted || u.events.active()) && !u.batched {
+		return u.atomic(func(tx *undirected[K, T]) error {
+			return tx.RemoveVertex(hash)
+		})
 	}
 
-	if _, _, err := u.store.Vertex(target); err != nil {
-		return err
+	event := Event[K]{Kind: VertexRemoved, Vertex: hash}
+
+	if u.events.active() {
+		_, properties, err := u.store.Vertex(hash)
+		if err != nil {
+			return err
+		}
+
+		degree, err := u.store.OutDegree(hash)
+		if err != nil {
+			return err
+		}
+
+		if degree > 0 {
+			return ErrorVertexHashEdges
+		}
+
+		event.VertexProperties = properties
 	}
 
-	//nolint:govet // False positive.
-	if _, err := u.Edge(source, target); !errors.Is(err, ErrorEdgeNotFound) {
-		return ErrorEdgeExists
+	return u.events.change(u.pending, event, func() error {
+		if err := u.store.RemoveVertex(hash); err != nil {
+			return err
+		}
+
+		u.traits.forgetRoot(hash)
+
+		return nil
+	})
+}
+
+// Удаляет вершину и её дуги под одной блокировкой. Слушатели получают
+// отдельное событие на каждую дугу и затем на вершину
+func (u *undirected[K, T]) RemoveVertexCascade(hash K) ([]Edge[K], error) {
+	var removed []Edge[K]
+
+	err := u.atomic(func(tx *undirected[K, T]) error {
+		if _, _, err := tx.store.Vertex(hash); err != nil {
+			return err
+		}
+
+		// Каждая дуга хранится в обе стороны, исходящих достаточно
+		edges, err := tx.store.OutEdges(hash)
+		if err != nil {
+			return err
+		}
+
+		// В дереве ребро к родителю снимается последним, когда hash уже лист
+		if tx.traits.IsTree {
+			if edges, err = tx.leavesFirst(edges); err != nil {
+				return err
+			}
+		}
+
+		removed = make([]Edge[K], 0, len(edges))
+
+		for _, edge := range edges {
+			if err := tx.removeListedEdge(edge); err != nil {
+				return err
+			}
+			removed = append(removed, edge)
+		}
+
+		return tx.RemoveVertex(hash)
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	return removed, nil
+}
+
+func (u *undirected[K, T]) EditVertex(hash K, options ...func(*VertexProperties)) error {
+	// Чтение старых свойств и запись без вмешательства других горутин
+	return u.atomicPair(hash, hash, func(tx *undirected[K, T]) error {
+		_, properties, err := tx.store.Vertex(hash)
+		if err != nil {
+			return err
+		}
+
+		before := properties
+
+		// Карта атрибутов общая с хранилищем, правим копию
+		properties.Attributes = copyAttributes(properties.Attributes)
+
+		for _, option := range options {
+			option(&properties)
+		}
+		tx.traits.syncVertexWeight(&properties)
+
+		event := Event[K]{Kind: VertexEdited, Vertex: hash, VertexProperties: properties, VertexBefore: before}
+
+		return tx.events.change(tx.pending, event, func() error {
+			return tx.store.EditVertex(hash, properties)
+		})
+	})
+}
+
+func (u *undirected[K, T]) AddEdge(source, target K, options ...func(*EdgeProperties)) error {
+	_, err := u.AddEdgeWithID(source, target, options...)
+	return err
+}
+
+func (u *undirected[K, T]) AddEdgeWithID(source, target K, options ...func(*EdgeProperties)) (uint64, error) {
 	edge := Edge[K]{
 		Source: source,
 		Target: target,
@@ -83,12 +220,62 @@ func (u *undirected[K, T]) AddEdge(source, target K, options ...func(*EdgeProper
 	for _, option := range options {
 		option(&edge.Properties)
 	}
+	u.traits.syncEdgeWeight(&edge.Properties)
 
-	if err := u.addEdge(source, target, edge); err != nil {
+	insert := func(tx *undirected[K, T]) error {
+		return tx.insertEdge(&edge)
+	}
+
+	// Обе половины дуги пишутся под одной блокировкой, при ошибке первая откатывается.
+	// Свой ID дуги проверяется по всему графу, тогда блокируется всё хранилище
+	var err error
+	if u.traits.IsMultigraph && edge.Properties.ID != 0 {
+		err = u.atomic(insert)
+	} else {
+		err = u.atomicPair(source, target, insert)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return edge.Properties.ID, nil
+}
+
+func (u *undirected[K, T]) insertEdge(edge *Edge[K]) error {
+	source, target := edge.Source, edge.Target
+
+	if _, _, err := u.store.Vertex(source); err != nil {
 		return err
 	}
 
-	return nil
+	if _, _, err := u.store.Vertex(target); err != nil {
+		return err
+	}
+
+	if u.traits.IsMultigraph {
+		if err := takeEdgeID(u.store, u.ids, &edge.Properties); err != nil {
+			return err
+		}
+	} else {
+		//nolint:govet // False positive.
+		if _, err := u.Edge(source, target); !errors.Is(err, ErrorEdgeNotFound) {
+			return ErrorEdgeExists
+		}
+
+		edge.Properties.ID = 0
+	}
+
+	if u.traits.IsTree {
+		if err := u.checkTreeEdge(source, target); err != nil {
+			return err
+		}
+	}
+
+	event := Event[K]{Kind: EdgeAdded, Source: source, Target: target, After: edge.Properties}
+
+	return u.events.change(u.pending, event, func() error {
+		return u.addEdge(source, target, *edge)
+	})
 }
 
 func (u *undirected[K, T]) AddEdgesFrom(g Graph[K, T]) error {
@@ -127,9 +314,21 @@ func (u *undirected[K, T]) AddVerticesFrom(g Graph[K, T]) error {
 }
 
 func (u *undirected[K, T]) Edge(source, target K) (Edge[T], error) {
-	// In an undirected graph, since multigraphs aren't supported, the edge AB
-	// is the same as BA. Therefore, if source[target] cannot be found, this
-	// function also looks for target[source].
+	var res Edge[T]
+
+	// Дугу и её вершины читаем согласованно
+	err := u.read(func(view *undirected[K, T]) error {
+		var err error
+		res, err = view.edge(source, target)
+		return err
+	})
+
+	return res, err
+}
+
+func (u *undirected[K, T]) edge(source, target K) (Edge[T], error) {
+	// In an undirected graph the edge AB is the same as BA. Therefore, if
+	// source[target] cannot be found, this function also looks for target[source].
 	edge, err := u.store.Edge(source, target)
 	if errors.Is(err, ErrorEdgeNotFound) {
 		edge, err = u.store.Edge(target, source)
@@ -153,9 +352,12 @@ func (u *undirected[K, T]) Edge(source, target K) (Edge[T], error) {
 		Source: sourceVertex,
 		Target: targetVertex,
 		Properties: EdgeProperties{
-			Weight:     edge.Properties.Weight,
-			Attributes: edge.Properties.Attributes,
-			Data:       edge.Properties.Data,
+			Weight:      edge.Properties.Weight,
+			FloatWeight: edge.Properties.FloatWeight,
+			Attributes:  edge.Properties.Attributes,
+			Data:        edge.Properties.Data,
+			ID:          edge.Properties.ID,
+			Type:        edge.Properties.Type,
 		},
 	}, nil
 }
@@ -164,6 +366,12 @@ type tuple[K comparable] struct {
 	source, target K
 }
 
+// Пара вершин вместе с ID дуги мультиграфа
+type edgeKey[K comparable] struct {
+	tuple[K]
+	id uint64
+}
+
 func (u *undirected[K, T]) Edges() ([]Edge[K], error) {
 	storedEdges, err := u.store.ListEdges()
 	if err != nil {
@@ -171,12 +379,13 @@ func (u *undirected[K, T]) Edges() ([]Edge[K], error) {
 	}
 
 	edges := make([]Edge[K], 0, len(storedEdges)/2)
-	added := make(map[tuple[K]]struct{})
+	added := make(map[edgeKey[K]]struct{})
 
 	for _, storedEdge := range storedEdges {
-		reversedEdge := tuple[K]{
-			source: storedEdge.Target,
-			target: storedEdge.Source,
+		// Параллельные дуги различаются по ID, у двух половин одной дуги он общий
+		reversedEdge := edgeKey[K]{
+			tuple: tuple[K]{source: storedEdge.Target, target: storedEdge.Source},
+			id:    storedEdge.Properties.ID,
 		}
 		if _, ok := added[reversedEdge]; ok {
 			continue
@@ -184,9 +393,9 @@ func (u *undirected[K, T]) Edges() ([]Edge[K], error) {
 
 		edges = append(edges, storedEdge)
 
-		addedEdge := tuple[K]{
-			source: storedEdge.Source,
-			target: storedEdge.Target,
+		addedEdge := edgeKey[K]{
+			tuple: tuple[K]{source: storedEdge.Source, target: storedEdge.Target},
+			id:    storedEdge.Properties.ID,
 		}
 
 		added[addedEdge] = struct{}{}
@@ -196,43 +405,89 @@ func (u *undirected[K, T]) Edges() ([]Edge[K], error) {
 }
 
 func (u *undirected[K, T]) EditEdge(source, target K, options ...func(properties *EdgeProperties)) error {
-	existingEdge, err := u.store.Edge(source, target)
-	if err != nil {
-		return err
-	}
+	// Чтение старых свойств и запись без вмешательства других горутин
+	return u.atomicPair(source, target, func(tx *undirected[K, T]) error {
+		if tx.traits.IsMultigraph {
+			return tx.eachParallelEdge(source, target, func(edge Edge[K]) error {
+				return tx.editParallelEdge(edge, options)
+			})
+		}
 
-	for _, option := range options {
-		option(&existingEdge.Properties)
-	}
+		existingEdge, err := tx.store.Edge(source, target)
+		if err != nil {
+			return err
+		}
 
-	if err := u.store.EditEdge(source, target, existingEdge); err != nil {
-		return err
-	}
+		before := existingEdge.Properties
+
+		// Карта атрибутов общая с хранилищем, правим копию
+		existingEdge.Properties.Attributes = copyAttributes(existingEdge.Properties.Attributes)
+
+		for _, option := range options {
+			option(&existingEdge.Properties)
+		}
+		tx.traits.syncEdgeWeight(&existingEdge.Properties)
 
-	reversedEdge := existingEdge
-	reversedEdge.Source = existingEdge.Target
-	reversedEdge.Target = existingEdge.Source
+		event := Event[K]{Kind: EdgeEdited, Source: source, Target: target, Before: before, After: existingEdge.Properties}
 
-	return u.store.EditEdge(target, source, reversedEdge)
+		return tx.events.change(tx.pending, event, func() error {
+			if err := tx.store.EditEdge(source, target, existingEdge); err != nil {
+				return err
+			}
+
+			reversedEdge := existingEdge
+			reversedEdge.Source = existingEdge.Target
+			reversedEdge.Target = existingEdge.Source
+
+			return tx.store.EditEdge(target, source, reversedEdge)
+		})
+	})
 }
 
 func (u *undirected[K, T]) RemoveEdge(source, target K) error {
-	if _, err := u.Edge(source, target); err != nil {
-		return err
-	}
+	// Обе половины дуги удаляются под одной блокировкой хранилища
+	return u.atomicPair(source, target, func(tx *undirected[K, T]) error {
+		if tx.traits.IsMultigraph {
+			return tx.eachParallelEdge(source, target, tx.removeParallelEdge)
+		}
 
-	if err := u.store.RemoveEdge(source, target); err != nil {
-		return err
-	}
+		existingEdge, err := tx.store.Edge(source, target)
+		if err != nil {
+			return err
+		}
 
-	if err := u.store.RemoveEdge(target, source); err != nil {
-		return err
-	}
+		if tx.traits.IsTree {
+			if err := tx.checkTreeRemoval(source, target); err != nil {
+				return err
+			}
+		}
 
-	return nil
+		event := Event[K]{Kind: EdgeRemoved, Source: source, Target: target, Before: existingEdge.Properties}
+
+		return tx.events.change(tx.pending, event, func() error {
+			if err := tx.store.RemoveEdge(source, target); err != nil {
+				return err
+			}
+
+			return tx.store.RemoveEdge(target, source)
+		})
+	})
 }
 
 func (u *undirected[K, T]) AdjacencyMap() (map[K]map[K]Edge[K], error) {
+	var res map[K]map[K]Edge[K]
+
+	// Вершины и дуги читаем согласованно, иначе дуга может сослаться на вершину не из списка
+	err := u.read(func(view *undirected[K, T]) error {
+		var err error
+		res, err = view.adjacencyMap()
+		return err
+	})
+
+	return res, err
+}
+
+func (u *undirected[K, T]) adjacencyMap() (map[K]map[K]Edge[K], error) {
 	vertices, err := u.store.ListVertices()
 	if err != nil {
 		return nil, err
@@ -256,18 +511,151 @@ func (u *undirected[K, T]) AdjacencyMap() (map[K]map[K]Edge[K], error) {
 	return m, nil
 }
 
+func (u *undirected[K, T]) PredecessorMap() (map[K]map[K]Edge[K], error) {
+	return u.AdjacencyMap()
+}
+
+func (u *undirected[K, T]) Neighbors(hash K) ([]K, error) {
+	return u.store.Neighbors(hash)
+}
+
+func (u *undirected[K, T]) Predecessors(hash K) ([]K, error) {
+	return u.store.Neighbors(hash)
+}
+
+func (u *undirected[K, T]) OutEdges(hash K) ([]Edge[K], error) {
+	return u.store.OutEdges(hash)
+}
+
+func (u *undirected[K, T]) InEdges(hash K) ([]Edge[K], error) {
+	return u.store.InEdges(hash)
+}
+
+// Выполняет группу изменений атомарно: хранилище блокируется один раз,
+// а при ошибке fn все изменения откатываются
+func (u *undirected[K, T]) Batch(fn func(tx Graph[K, T]) error) error {
+	return u.atomic(func(tx *undirected[K, T]) error {
+		return fn(tx)
+	})
+}
+
+// Выполняет чтение fn над согласованным видом хранилища.
+// Внутри Batch хранилище уже заблокировано, поэтому просто вызывает fn
+func (u *undirected[K, T]) read(fn func(view *undirected[K, T]) error) error {
+	if u.batched {
+		return fn(u)
+	}
+
+	return runView(u.store, func(store Store[K, T]) error {
+		return fn(u.tx(store))
+	})
+}
+
+func (u *undirected[K, T]) readView(fn func(view Graph[K, T]) error) error {
+	return u.read(func(view *undirected[K, T]) error {
+		return fn(view)
+	})
+}
+
+// Как atomic, но блокирует только вершины source и target, если хранилище это умеет
+func (u *undirected[K, T]) atomicPair(source, target K, fn func(tx *undirected[K, T]) error) error {
+	if u.batched {
+		return u.atomic(fn)
+	}
+
+	return runBatchKeys(u.store, []K{source, target}, u.commit(fn))
+}
+
+// Выполняет fn над графом поверх заблокированного хранилища.
+// Внутри уже идущего Batch просто вызывает fn
+func (u *undirected[K, T]) atomic(fn func(tx *undirected[K, T]) error) error {
+	if u.batched {
+		return fn(u)
+	}
+
+	return runBatch(u.store, u.commit(fn))
+}
+
+// Тело транзакции над заблокированным хранилищем.
+// События подписчикам уходят только после успеха fn, пока хранилище ещё заблокировано,
+// поэтому подписчики видят изменения в том же порядке, в каком они применились
+func (u *undirected[K, T]) commit(fn func(tx *undirected[K, T]) error) func(store Store[K, T]) error {
+	return func(store Store[K, T]) error {
+		tx := u.tx(store)
+		tx.pending = new([]Event[K])
+
+		// Корень мог запомниться на вершине, которая откатится
+		var root any
+		if tx.traits.IsRooted {
+			root = tx.traits.root
+		}
+
+		if err := fn(tx); err != nil {
+			if tx.traits.IsRooted {
+				tx.traits.root = root
+			}
+			return err
+		}
+
+		u.events.publish(*tx.pending)
+
+		return nil
+	}
+}
+
+// Граф поверх уже заблокированного хранилища
+func (u *undirected[K, T]) tx(store Store[K, T]) *undirected[K, T] {
+	return &undirected[K, T]{
+		hash:    u.hash,
+		traits:  u.traits,
+		store:   store,
+		batched: true,
+		events:  u.events,
+		pending: u.pending,
+		ids:     u.ids,
+	}
+}
+
+func (u *undirected[K, T]) Observe(listener Listener[K]) func() {
+	return u.events.observe(listener)
+}
+
+func (u *undirected[K, T]) Subscribe(buffer int) (<-chan Event[K], func()) {
+	return u.events.subscribe(buffer)
+}
+
+func (u *undirected[K, T]) Snapshot() (*Snapshot[K, T], error) {
+	store, release, err := snapshotStore(u.store)
+	if err != nil {
+		return nil, err
+	}
+
+	// Корень может смениться, поэтому у снимка свои свойства
+	traits := *u.traits
+
+	return newSnapshot[K, T](newUndirected(u.hash, &traits, store), release), nil
+}
+
+func (u *undirected[K, T]) hashFunc() Hash[K, T] {
+	return u.hash
+}
+
+func (u *undirected[K, T]) storage() Store[K, T] {
+	return u.store
+}
+
 func (u *undirected[K, T]) Clone() (Graph[K, T], error) {
 	traits := &Traits{
-		IsDirected: u.traits.IsDirected,
-		IsWeighted: u.traits.IsWeighted,
-		IsRooted:   u.traits.IsRooted,
+		IsDirected:      u.traits.IsDirected,
+		IsWeighted:      u.traits.IsWeighted,
+		IsFloatWeighted: u.traits.IsFloatWeighted,
+		IsRooted:        u.traits.IsRooted,
+		IsTree:          u.traits.IsTree,
+		IsMultigraph:    u.traits.IsMultigraph,
+		root:            currentRoot[K, T](u),
 	}
 
-	clone := &undirected[K, T]{
-		hash:   u.hash,
-		traits: traits,
-		store:  newStore[K, T](),
-	}
+	clone := newUndirected(u.hash, traits, emptyLike(u.store))
 
 	if err := clone.AddVerticesFrom(u); err != nil {
 		return nil, err
@@ -285,42 +673,375 @@ func (u *undirected[K, T]) Order() (int, error) {
 }
 
 func (u *undirected[K, T]) Size() (int, error) {
-	size := 0
+	var size int
+
+	// Половины дуг и петли считаем на одном виде хранилища
+	err := u.read(func(view *undirected[K, T]) error {
+		halves, err := edgeCount[K, T](view.store)
+		if err != nil {
+			return err
+		}
+
+		vertices, err := view.store.ListVertices()
+		if err != nil {
+			return err
+		}
+
+		// Каждая дуга хранится двумя половинами, а петля одной
+		loops := 0
+		for _, vertex := range vertices {
+			count, err := view.loops(vertex)
+			if err != nil {
+				return err
+			}
+			loops += count
+		}
+
+		size = (halves + loops) / 2
+		return nil
+	})
+
+	return size, err
+}
+
+// Число петель у вершины
+func (u *undirected[K, T]) loops(hash K) (int, error) {
+	if u.traits.IsMultigraph {
+		multi, err := multiEdges(u.store)
+		if err != nil {
+			return 0, err
+		}
+
+		edges, err := multi.EdgesBetween(hash, hash)
+		return len(edges), err
+	}
 
-	outEdges, err := u.AdjacencyMap()
+	_, err := u.store.Edge(hash, hash)
+	if errors.Is(err, ErrorEdgeNotFound) {
+		return 0, nil
+	}
 	if err != nil {
 		return 0, err
 	}
 
-	for _, outEdges := range outEdges {
-		size += len(outEdges)
+	return 1, nil
+}
+
+// Ненаправленное дерево растёт от корня: ребро соединяет вершину, уже
+// прикреплённую к дереву (корень или вершину с рёбрами), со свободной вершиной
+// без рёбер. Так любая вершина с рёбрами связана с корнем, и цикл не замкнуть.
+// Проверка смотрит только на степени концов ребра
+func (u *undirected[K, T]) checkTreeEdge(source, target K) error {
+	sourceAttached, err := u.attachedToTree(source)
+	if err != nil {
+		return err
+	}
+
+	targetAttached, err := u.attachedToTree(target)
+	if err != nil {
+		return err
+	}
+
+	if sourceAttached == targetAttached {
+		return ErrorEdgeBreaksTree
 	}
 
-	// Divide by 2 since every add edge operation on undirected graph is counted
-	// twice.
-	return size / 2, nil
+	return nil
+}
+
+// Удалить из дерева можно только ребро к листу, иначе
+// оторванная часть потеряет связь с корнем
+func (u *undirected[K, T]) checkTreeRemoval(source, target K) error {
+	for _, end := range []K{source, target} {
+		if root, ok := u.traits.root.(K); ok && root == end {
+			continue
+		}
+
+		degree, err := u.store.OutDegree(end)
+		if err != nil {
+			return err
+		}
+
+		if degree == 1 {
+			return nil
+		}
+	}
+
+	return ErrorEdgeBreaksTree
+}
+
+// Переставляет рёбра так, что рёбра к листьям идут первыми
+func (u *undirected[K, T]) leavesFirst(edges []Edge[K]) ([]Edge[K], error) {
+	leaves := make([]Edge[K], 0, len(edges))
+	rest := make([]Edge[K], 0, 1)
+
+	for _, edge := range edges {
+		degree, err := u.store.OutDegree(edge.Target)
+		if err != nil {
+			return nil, err
+		}
+
+		if root, ok := u.traits.root.(K); degree == 1 && (!ok || root != edge.Target) {
+			leaves = append(leaves, edge)
+		} else {
+			rest = append(rest, edge)
+		}
+	}
+
+	return append(leaves, rest...), nil
+}
+
+func (u *undirected[K, T]) attachedToTree(hash K) (bool, error) {
+	if root, ok := u.traits.root.(K); ok && root == hash {
+		return true, nil
+	}
+
+	degree, err := u.store.OutDegree(hash)
+
+	return degree > 0, err
+}
+
+// Каждая дуга хранится в обе стороны, поэтому полустепени совпадают
+// и равны числу соседей с учётом параллельных дуг. Петля в них входит один раз
+func (u *undirected[K, T]) InDegree(hash K) (int, error) {
+	return u.store.OutDegree(hash)
+}
+
+func (u *undirected[K, T]) OutDegree(hash K) (int, error) {
+	return u.store.OutDegree(hash)
+}
+
+// Степень вершины, где петля, как принято, считается дважды
+func (u *undirected[K, T]) Degree(hash K) (int, error) {
+	var degree int
+
+	err := u.read(func(view *undirected[K, T]) error {
+		out, err := view.store.OutDegree(hash)
+		if err != nil {
+			return err
+		}
+
+		loops, err := view.loops(hash)
+		if err != nil {
+			return err
+		}
+
+		degree = out + loops
+		return nil
+	})
+
+	return degree, err
 }
 
 func (u *undirected[K, T]) addEdge(source, target K, edge Edge[K]) error {
+	if u.traits.IsMultigraph {
+		return u.addParallelEdge(source, target, edge)
+	}
+
 	err := u.store.AddEdge(source, target, edge)
 	if err != nil {
 		return err
 	}
 
-	rEdge := Edge[K]{
+	rEdge := reversed(edge)
+
+	err = u.store.AddEdge(target, source, rEdge)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Вторая половина дуги ненаправленного графа
+func reversed[K comparable](edge Edge[K]) Edge[K] {
+	return Edge[K]{
 		Source: edge.Target,
 		Target: edge.Source,
 		Properties: EdgeProperties{
-			Weight:     edge.Properties.Weight,
-			Attributes: edge.Properties.Attributes,
-			Data:       edge.Properties.Data,
+			Weight:      edge.Properties.Weight,
+			FloatWeight: edge.Properties.FloatWeight,
+			Attributes:  edge.Properties.Attributes,
+			Data:        edge.Properties.Data,
+			ID:          edge.Properties.ID,
+			Type:        edge.Properties.Type,
 		},
 	}
+}
 
-	err = u.store.AddEdge(target, source, rEdge)
+// Половины дуги мультиграфа с общим ID. Петля хранится одной половиной
+func (u *undirected[K, T]) addParallelEdge(source, target K, edge Edge[K]) error {
+	multi, err := multiEdges(u.store)
 	if err != nil {
 		return err
 	}
 
+	if err := multi.AddParallelEdge(source, target, edge); err != nil {
+		return err
+	}
+
+	if source == target {
+		return nil
+	}
+
+	return multi.AddParallelEdge(target, source, reversed(edge))
+}
+
+func (u *undirected[K, T]) EdgesBetween(source, target K) ([]Edge[K], error) {
+	if u.traits.IsMultigraph {
+		multi, err := multiEdges(u.store)
+		if err != nil {
+			return nil, err
+		}
+
+		return multi.EdgesBetween(source, target)
+	}
+
+	edge, err := u.store.Edge(source, target)
+	if errors.Is(err, ErrorEdgeNotFound) {
+		return []Edge[K]{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return []Edge[K]{edge}, nil
+}
+
+func (u *undirected[K, T]) EdgeByID(id uint64) (Edge[K], error) {
+	if !u.traits.IsMultigraph {
+		return Edge[K]{}, ErrorNotMultigraph
+	}
+
+	multi, err := multiEdges(u.store)
+	if err != nil {
+		return Edge[K]{}, err
+	}
+
+	return multi.EdgeByID(id)
+}
+
+func (u *undirected[K, T]) EditEdgeByID(id uint64, options ...func(properties *EdgeProperties)) error {
+	return u.withEdgeID(id, func(tx *undirected[K, T], edge Edge[K]) error {
+		return tx.editParallelEdge(edge, options)
+	})
+}
+
+func (u *undirected[K, T]) RemoveEdgeByID(id uint64) error {
+	return u.withEdgeID(id, func(tx *undirected[K, T], edge Edge[K]) error {
+		return tx.removeParallelEdge(edge)
+	})
+}
+
+// Находит дугу по ID и вызывает fn под блокировкой её вершин
+func (u *undirected[K, T]) withEdgeID(id uint64, fn func(tx *undirected[K, T], edge Edge[K]) error) error {
+	edge, err := u.EdgeByID(id)
+	if err != nil {
+		return err
+	}
+
+	return u.atomicPair(edge.Source, edge.Target, func(tx *undirected[K, T]) error {
+		multi, err := multiEdges(tx.store)
+		if err != nil {
+			return err
+		}
+
+		// До блокировки дугу могли изменить или удалить
+		current, err := parallelEdge(multi.EdgesBetween, edge.Source, edge.Target, id)
+		if err != nil {
+			return err
+		}
+
+		return fn(tx, current)
+	})
+}
+
+// Вызывает fn для каждой дуги пары. Пустая пара это ErrorEdgeNotFound
+func (u *undirected[K, T]) eachParallelEdge(source, target K, fn func(edge Edge[K]) error) error {
+	edges, err := u.EdgesBetween(source, target)
+	if err != nil {
+		return err
+	}
+
+	if len(edges) == 0 {
+		return ErrorEdgeNotFound
+	}
+
+	for _, edge := range edges {
+		if err := fn(edge); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+func (u *undirected[K, T]) editParallelEdge(edge Edge[K], options []func(properties *EdgeProperties)) error {
+	multi, err := multiEdges(u.store)
+	if err != nil {
+		return err
+	}
+
+	before := edge.Properties
+
+	// Карта атрибутов общая с хранилищем, правим копию
+	edge.Properties.Attributes = copyAttributes(edge.Properties.Attributes)
+
+	for _, option := range options {
+		option(&edge.Properties)
+	}
+	u.traits.syncEdgeWeight(&edge.Properties)
+
+	// ID дуги не меняется
+	edge.Properties.ID = before.ID
+
+	event := Event[K]{Kind: EdgeEdited, Source: edge.Source, Target: edge.Target, Before: before, After: edge.Properties}
+
+	return u.events.change(u.pending, event, func() error {
+		if err := multi.EditParallelEdge(edge.Source, edge.Target, edge); err != nil {
+			return err
+		}
+
+		if edge.Source == edge.Target {
+			return nil
+		}
+
+		return multi.EditParallelEdge(edge.Target, edge.Source, reversed(edge))
+	})
+}
+
+func (u *undirected[K, T]) removeParallelEdge(edge Edge[K]) error {
+	multi, err := multiEdges(u.store)
+	if err != nil {
+		return err
+	}
+
+	if u.traits.IsTree {
+		if err := u.checkTreeRemoval(edge.Source, edge.Target); err != nil {
+			return err
+		}
+	}
+
+	event := Event[K]{Kind: EdgeRemoved, Source: edge.Source, Target: edge.Target, Before: edge.Properties}
+
+	return u.events.change(u.pending, event, func() error {
+		if err := multi.RemoveParallelEdge(edge.Source, edge.Target, edge.Properties.ID); err != nil {
+			return err
+		}
+
+		if edge.Source == edge.Target {
+			return nil
+		}
+
+		return multi.RemoveParallelEdge(edge.Target, edge.Source, edge.Properties.ID)
+	})
+}
+
+// Удаляет дугу из списка хранилища. В мультиграфе только её, а не всю пару
+func (u *undirected[K, T]) removeListedEdge(edge Edge[K]) error {
+	if u.traits.IsMultigraph {
+		return u.removeParallelEdge(edge)
+	}
+
+	return u.RemoveEdge(edge.Source, edge.Target)
+}
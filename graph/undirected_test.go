@@ -0,0 +1,95 @@
+package graph
+
+import "testing"
+
+// Петля хранится одной половиной, но в Size это одна дуга, а в Degree два конца
+func TestUndirectedSelfLoop(t *testing.T) {
+	kinds := []struct {
+		name    string
+		options []func(*Traits)
+	}{
+		{"simple", nil},
+		{"multigraph", []func(*Traits){Multigraph()}},
+	}
+
+	for _, kind := range kinds {
+		t.Run(kind.name, func(t *testing.T) {
+			g := New(IntHash, kind.options...)
+
+			for _, v := range []int{1, 2} {
+				if err := g.AddVertex(v); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := g.AddEdge(1, 1); err != nil {
+				t.Fatal(err)
+			}
+			if err := g.AddEdge(1, 2); err != nil {
+				t.Fatal(err)
+			}
+
+			frozen, err := Freeze(g)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for name, graph := range map[string]Graph[int, int]{"graph": g, "frozen": frozen} {
+				edges, err := graph.Edges()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				size, err := graph.Size()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if size != 2 || len(edges) != 2 {
+					t.Fatalf("%s: Size() = %d, Edges() = %d, ожидалось 2", name, size, len(edges))
+				}
+
+				degree, err := graph.Degree(1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if degree != 3 {
+					t.Fatalf("%s: Degree(1) = %d, ожидалось 3", name, degree)
+				}
+
+				degree, err = graph.Degree(2)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if degree != 1 {
+					t.Fatalf("%s: Degree(2) = %d, ожидалось 1", name, degree)
+				}
+			}
+		})
+	}
+}
+
+// Петля снимается одним ребром, каждое ребро попадает в удалённые один раз
+func TestUndirectedRemoveVertexCascade(t *testing.T) {
+	kinds := []struct {
+		name    string
+		options []func(*Traits)
+	}{
+		{"simple", nil},
+		{"multigraph", []func(*Traits){Multigraph()}},
+	}
+
+	for _, kind := range kinds {
+		t.Run(kind.name, func(t *testing.T) {
+			g, ids := cascadeGraph(t, kind.options...)
+
+			removed, err := g.RemoveVertexCascade(2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkCascade(t, g, removed, ids)
+
+			if degree, _ := g.Degree(1); degree != 1 {
+				t.Fatalf("Degree(1) = %d, ожидалось 1", degree)
+			}
+		})
+	}
+}
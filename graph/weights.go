@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Вес дуги для взвешенных алгоритмов. Один и тот же граф можно считать
+// по разным метрикам, передавая разные WeightFunc. Ошибка прерывает алгоритм
+type WeightFunc[K comparable] func(edge Edge[K]) (float64, error)
+
+type WeightOptions[K comparable] struct {
+	// Вес дуги. По умолчанию Properties.Weight,
+	// у графа FloatWeighted Properties.FloatWeight
+	Weight WeightFunc[K]
+}
+
+// Своя функция веса для алгоритма
+func WeightBy[K comparable](fn WeightFunc[K]) func(*WeightOptions[K]) {
+	return func(o *WeightOptions[K]) {
+		o.Weight = fn
+	}
+}
+
+func weightOptions[K comparable](traits *Traits, options []func(*WeightOptions[K])) WeightOptions[K] {
+	opts := WeightOptions[K]{
+		Weight: PropertyWeight[K],
+	}
+
+	if traits.IsFloatWeighted {
+		opts.Weight = FloatPropertyWeight[K]
+	}
+
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return opts
+}
+
+// Вес из Properties.Weight
+func PropertyWeight[K comparable](edge Edge[K]) (float64, error) {
+	return float64(edge.Properties.Weight), nil
+}
+
+// Вес из Properties.FloatWeight
+func FloatPropertyWeight[K comparable](edge Edge[K]) (float64, error) {
+	return edge.Properties.FloatWeight, nil
+}
+
+// Вес из атрибута дуги, например WeightFromAttribute[string]("latency").
+// Нет атрибута это ErrorWeightMissing, не число это ErrorWeightNotNumber вместе с ошибкой разбора
+func WeightFromAttribute[K comparable](name string) WeightFunc[K] {
+	return func(edge Edge[K]) (float64, error) {
+		value, ok := edge.Properties.Attributes[name]
+		if !ok {
+			return 0, ErrorWeightMissing
+		}
+
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, errors.Join(ErrorWeightNotNumber, err)
+		}
+
+		return weight, nil
+	}
+}
+
+// Вес из Properties.Data типа D. Data другого типа это ErrorWeightDataType
+func WeightFromData[K comparable, D any](weight func(data D) float64) WeightFunc[K] {
+	return func(edge Edge[K]) (float64, error) {
+		data, ok := edge.Properties.Data.(D)
+		if !ok {
+			return 0, ErrorWeightDataType
+		}
+
+		return weight(data), nil
+	}
+}
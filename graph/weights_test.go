@@ -0,0 +1,145 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// У обычного графа главный Weight, у FloatWeighted дробный FloatWeight
+func TestFloatWeighted(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []func(*Traits)
+		// Путь 1 -> 3 напрямую весит 3.5, через 2 весит 1.9 + 1.9.
+		// Целые части дают 3 и 1 + 1
+		path     []int
+		distance float64
+		// Веса вершины и дуги 1 -> 2 после EditEdge(1, 2, EdgeWeightOf(1.7))
+		weight      int
+		floatWeight float64
+	}{
+		{"int", []func(*Traits){Directed(), Weighted()}, []int{1, 2, 3}, 2, 1, 1},
+		{"float", []func(*Traits){Directed(), FloatWeighted()}, []int{1, 3}, 3.5, 1, 1.7},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := New(IntHash, test.options...)
+			for i := 1; i <= 3; i++ {
+				if err := g.AddVertex(i); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_ = g.AddEdge(1, 2, EdgeWeightFloat(1.9))
+			_ = g.AddEdge(2, 3, EdgeWeightFloat(1.9))
+			_ = g.AddEdge(1, 3, EdgeWeightFloat(3.5))
+
+			path, distance, err := ShortestPath(g, 1, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(path, test.path) || distance != test.distance {
+				t.Fatalf("ShortestPath = %v, %v, ожидалось %v, %v", path, distance, test.path, test.distance)
+			}
+
+			if err := g.EditEdge(1, 2, EdgeWeightOf(1.7)); err != nil {
+				t.Fatal(err)
+			}
+			edge, err := g.Edge(1, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if edge.Properties.Weight != test.weight || edge.Properties.FloatWeight != test.floatWeight {
+				t.Fatalf("вес дуги %d и %v, ожидалось %d и %v",
+					edge.Properties.Weight, edge.Properties.FloatWeight, test.weight, test.floatWeight)
+			}
+		})
+	}
+}
+
+// Опция, пишущая только Weight, у обычного графа попадает и в FloatWeight
+func TestWeightFieldSync(t *testing.T) {
+	g := New(IntHash, Weighted())
+
+	setWeight := func(p *VertexProperties) {
+		p.Weight = 4
+	}
+	if err := g.AddVertex(1, setWeight); err != nil {
+		t.Fatal(err)
+	}
+
+	_, properties, err := g.VertexWithProperties(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if properties.FloatWeight != 4 {
+		t.Fatalf("FloatWeight = %v, ожидалось 4", properties.FloatWeight)
+	}
+
+	found, err := VerticesWithWeight(g, 3.5, 4.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(found, []int{1}) {
+		t.Fatalf("VerticesWithWeight = %v", found)
+	}
+}
+
+func TestWeightFromAttribute(t *testing.T) {
+	weight := WeightFromAttribute[int]("latency")
+
+	tests := []struct {
+		name       string
+		attributes map[string]string
+		want       float64
+		err        error
+	}{
+		{"целое", map[string]string{"latency": "12"}, 12, nil},
+		{"дробное", map[string]string{"latency": "0.25"}, 0.25, nil},
+		{"нет атрибута", map[string]string{"cost": "1"}, 0, ErrorWeightMissing},
+		{"не число", map[string]string{"latency": "fast"}, 0, ErrorWeightNotNumber},
+		{"пустая строка", map[string]string{"latency": ""}, 0, ErrorWeightNotNumber},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := weight(Edge[int]{Source: 1, Target: 2, Properties: EdgeProperties{Attributes: test.attributes}})
+			if !errors.Is(err, test.err) {
+				t.Fatalf("ошибка %v, ожидалась %v", err, test.err)
+			}
+			if got != test.want {
+				t.Fatalf("вес %v, ожидался %v", got, test.want)
+			}
+
+			// Вместе с ErrorWeightNotNumber приходит и ошибка разбора
+			var syntax *strconv.NumError
+			if errors.Is(test.err, ErrorWeightNotNumber) && !errors.As(err, &syntax) {
+				t.Fatalf("в %v нет ошибки разбора", err)
+			}
+		})
+	}
+}
+
+func TestWeightFromData(t *testing.T) {
+	type link struct {
+		meters float64
+	}
+
+	weight := WeightFromData[int](func(data link) float64 {
+		return data.meters / 1000
+	})
+
+	got, err := weight(Edge[int]{Properties: EdgeProperties{Data: link{meters: 1500}}})
+	if err != nil || got != 1.5 {
+		t.Fatalf("вес %v, ошибка %v", got, err)
+	}
+
+	for _, data := range []any{nil, "1500", &link{meters: 1500}} {
+		if _, err := weight(Edge[int]{Properties: EdgeProperties{Data: data}}); !errors.Is(err, ErrorWeightDataType) {
+			t.Fatalf("Data %#v, ошибка %v", data, err)
+		}
+	}
+}
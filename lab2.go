@@ -28,21 +28,14 @@ func main() {
 	_ = g.AddEdge(5, 6)
 
 	index := 4 // Наша заданная вершина
-	// Достаем все дуги
-	edges, _ := g.Edges()
-
-	sum := make(map[int]int)
-	// Инициализиурем нашу карту, во избежаннии пустых заходов
-	for _, edge := range edges {
-		sum[edge.Source] = 0
-	}
-	for _, edge := range edges {
-		sum[edge.Target]++
-	}
-
-	for edge, count := range sum {
-		if edge != index && count < sum[index] {
-			fmt.Printf("Вершина у которой полустепени заходи меньше чем у %d: %v\n", index, edge)
+	// Полустепень захода заданной вершины
+	indexDegree, _ := g.InDegree(index)
+
+	vertices, _ := g.AdjacencyMap()
+	for vertex := range vertices {
+		degree, _ := g.InDegree(vertex)
+		if vertex != index && degree < indexDegree {
+			fmt.Printf("Вершина у которой полустепени заходи меньше чем у %d: %v\n", index, vertex)
 		}
 	}
 
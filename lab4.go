@@ -25,21 +25,12 @@ func main() {
 	_ = g.AddEdge(5, 1)
 
 	index := 4 // Заданная вершина
-	vertexs, _ := g.AdjacencyMap()
-	for vertex := range vertexs {
-		if vertex == index {
-			continue
-		}
-
-		_ = graph.BFS(g, vertex, func(value int) bool {
-			if value == index {
-				fmt.Printf("Из вершины %v можно попасть в вершину %v\n", vertex, index)
-				return true
-			}
-			return false
-		})
-
+	// Все вершины, из которых есть путь в заданную
+	ancestors, _ := graph.Ancestors(g, index)
+	for _, vertex := range ancestors {
+		fmt.Printf("Из вершины %v можно попасть в вершину %v\n", vertex, index)
 	}
+
 	file, _ := os.Create("./test.gv")
 	_ = draw.DOT(g, file)
 
@@ -0,0 +1,9 @@
+package query
+
+import "errors"
+
+// Вынесенные заранее ошибки
+var (
+	ErrorSyntax          = errors.New("Ошибка в запросе")
+	ErrorUnknownVariable = errors.New("Переменная не встречается в шаблоне")
+)
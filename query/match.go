@@ -0,0 +1,408 @@
+package query
+
+import (
+	"slices"
+
+	"github.com/IvanSaratov/graph_methods/graph"
+)
+
+// Результат запроса. Rows[i][j] это вершина, связанная с переменной Columns[j].
+// Одинаковые строки не повторяются, порядок строк не задан
+type Result[K comparable] struct {
+	Columns []string
+	Rows    [][]K
+}
+
+// Разбирает и выполняет запрос, см. Parse
+func Run[K comparable, T any](g graph.Graph[K, T], text string) (*Result[K], error) {
+	q, err := Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return Match(g, q)
+}
+
+// Выполняет запрос на графе.
+// Каждый шаблон начинается с самой узкой вершины: уже связанной переменной
+// или вершины с наименьшим числом кандидатов по меткам и свойствам.
+// Кандидатов даёт graph.FindVertices, так что объявленные индексы атрибутов работают.
+// От начальной вершины шаблон разворачивается по дугам в обе стороны.
+// Связь переменной длины проверяет достижимость: вершины пути могут повторяться,
+// а разные пути к одной вершине дают одну строку
+func Match[K comparable, T any](g graph.Graph[K, T], q *Query) (*Result[K], error) {
+	m := &matcher[K, T]{
+		g:          g,
+		query:      q,
+		bindings:   make(map[string]K),
+		properties: make(map[K]graph.VertexProperties),
+		candidates: make(map[*node][]K),
+		seen:       make(map[any]struct{}),
+		result:     &Result[K]{Columns: q.columns, Rows: make([][]K, 0)},
+	}
+
+	if err := m.match(0); err != nil {
+		return nil, err
+	}
+
+	return m.result, nil
+}
+
+type matcher[K comparable, T any] struct {
+	g     graph.Graph[K, T]
+	query *Query
+
+	// Значения переменных на текущей ветке поиска
+	bindings map[string]K
+	// Свойства уже прочитанных вершин
+	properties map[K]graph.VertexProperties
+	// Кандидаты вершин шаблона без связанной переменной
+	candidates map[*node][]K
+
+	// Ключи уже выданных строк
+	seen   map[any]struct{}
+	result *Result[K]
+}
+
+// Сопоставляет шаблоны начиная с patterns[i]
+func (m *matcher[K, T]) match(i int) error {
+	if i == len(m.query.patterns) {
+		m.emit()
+		return nil
+	}
+
+	pattern := &m.query.patterns[i]
+
+	anchor, err := m.anchor(pattern)
+	if err != nil {
+		return err
+	}
+
+	starts, err := m.starts(&pattern.nodes[anchor])
+	if err != nil {
+		return err
+	}
+
+	at := make([]K, len(pattern.nodes))
+	steps := plan(pattern, anchor)
+
+	for _, start := range starts {
+		// Связанная раньше вершина ещё не проверена по этому шаблону
+		ok, err := m.matches(&pattern.nodes[anchor], start)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		err = m.bind(&pattern.nodes[anchor], start, func() error {
+			at[anchor] = start
+			return m.expand(pattern, steps, at, func() error {
+				return m.match(i + 1)
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Шаг разворота шаблона: от вершины from по связи rel к вершине to
+type step struct {
+	from, to int
+	rel      int
+	// Связь проходится справа налево
+	reverse bool
+}
+
+// Сначала вправо от начальной вершины, потом влево
+func plan(pattern *pattern, anchor int) []step {
+	steps := make([]step, 0, len(pattern.relationships))
+	for i := anchor; i < len(pattern.relationships); i++ {
+		steps = append(steps, step{from: i, to: i + 1, rel: i})
+	}
+
+	for i := anchor; i > 0; i-- {
+		steps = append(steps, step{from: i, to: i - 1, rel: i - 1, reverse: true})
+	}
+
+	return steps
+}
+
+// Номер вершины, с которой выгоднее начать шаблон
+func (m *matcher[K, T]) anchor(pattern *pattern) (int, error) {
+	best, size := 0, -1
+
+	for i := range pattern.nodes {
+		n := &pattern.nodes[i]
+		if _, ok := m.bindings[n.variable]; ok && n.variable != "" {
+			return i, nil
+		}
+
+		candidates, err := m.nodeCandidates(n)
+		if err != nil {
+			return 0, err
+		}
+
+		if size < 0 || len(candidates) < size {
+			best, size = i, len(candidates)
+		}
+	}
+
+	return best, nil
+}
+
+func (m *matcher[K, T]) starts(n *node) ([]K, error) {
+	if hash, ok := m.bindings[n.variable]; ok && n.variable != "" {
+		return []K{hash}, nil
+	}
+
+	return m.nodeCandidates(n)
+}
+
+// Вершины графа под метки и свойства n. Запоминаются на весь запрос
+func (m *matcher[K, T]) nodeCandidates(n *node) ([]K, error) {
+	if candidates, ok := m.candidates[n]; ok {
+		return candidates, nil
+	}
+
+	conditions := make([]graph.VertexCondition, 0, len(n.attributes)+len(n.labels))
+	for attribute, value := range n.attributes {
+		conditions = append(conditions, graph.AttributeEquals(attribute, value))
+	}
+	for _, label := range n.labels {
+		conditions = append(conditions, graph.HasLabel(label))
+	}
+
+	candidates, err := graph.FindVertices(m.g, conditions...)
+	if err != nil {
+		return nil, err
+	}
+
+	m.candidates[n] = candidates
+
+	return candidates, nil
+}
+
+// Выполняет шаги steps[0:], затем next
+func (m *matcher[K, T]) expand(pattern *pattern, steps []step, at []K, next func() error) error {
+	if len(steps) == 0 {
+		return next()
+	}
+
+	s := steps[0]
+
+	targets, err := m.reach(at[s.from], pattern.relationships[s.rel], s.reverse)
+	if err != nil {
+		return err
+	}
+
+	target := &pattern.nodes[s.to]
+
+	for _, hash := range targets {
+		ok, err := m.matches(target, hash)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		err = m.bind(target, hash, func() error {
+			at[s.to] = hash
+			return m.expand(pattern, steps[1:], at, next)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Связывает переменную n с hash на время fn.
+// Уже связанная с другой вершиной переменная ветку отсекает
+func (m *matcher[K, T]) bind(n *node, hash K, fn func() error) error {
+	if n.variable == "" {
+		return fn()
+	}
+
+	if bound, ok := m.bindings[n.variable]; ok {
+		if bound != hash {
+			return nil
+		}
+		return fn()
+	}
+
+	m.bindings[n.variable] = hash
+	defer delete(m.bindings, n.variable)
+
+	return fn()
+}
+
+// Подходит ли вершина под метки и свойства n
+func (m *matcher[K, T]) matches(n *node, hash K) (bool, error) {
+	if len(n.labels) == 0 && len(n.attributes) == 0 {
+		return true, nil
+	}
+
+	properties, ok := m.properties[hash]
+	if !ok {
+		var err error
+		if _, properties, err = m.g.VertexWithProperties(hash); err != nil {
+			return false, err
+		}
+		m.properties[hash] = properties
+	}
+
+	for _, label := range n.labels {
+		if !properties.HasLabel(label) {
+			return false, nil
+		}
+	}
+
+	for attribute, value := range n.attributes {
+		if actual, ok := properties.Attributes[attribute]; !ok || actual != value {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Вершины, до которых из from ведёт путь по связи rel подходящей длины
+func (m *matcher[K, T]) reach(from K, rel relationship, reverse bool) ([]K, error) {
+	if rel.min == 1 && rel.max == 1 {
+		return m.neighbors(from, rel, reverse)
+	}
+
+	res := make([]K, 0)
+	found := make(map[K]struct{})
+	add := func(hash K) {
+		if _, ok := found[hash]; !ok {
+			found[hash] = struct{}{}
+			res = append(res, hash)
+		}
+	}
+
+	if rel.min == 0 {
+		add(from)
+	}
+
+	// Без верхней границы длина больше min ничем не отличается от min,
+	// поэтому состояние это вершина и длина, обрезанная по min
+	limit := rel.max
+	if limit < 0 {
+		limit = rel.min
+	}
+
+	type state struct {
+		hash   K
+		length int
+	}
+
+	visited := map[state]struct{}{{from, 0}: {}}
+	layer := []K{from}
+
+	for length := 1; len(layer) > 0 && (rel.max < 0 || length <= rel.max); length++ {
+		next := make([]K, 0)
+
+		for _, hash := range layer {
+			neighbors, err := m.neighbors(hash, rel, reverse)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, neighbor := range neighbors {
+				s := state{neighbor, min(length, limit)}
+				if _, ok := visited[s]; ok {
+					continue
+				}
+				visited[s] = struct{}{}
+				next = append(next, neighbor)
+
+				if length >= rel.min {
+					add(neighbor)
+				}
+			}
+		}
+
+		layer = next
+	}
+
+	return res, nil
+}
+
+// Соседи по одной дуге связи rel. reverse меняет направление стрелки
+func (m *matcher[K, T]) neighbors(hash K, rel relationship, reverse bool) ([]K, error) {
+	dir := rel.direction
+	if reverse {
+		switch dir {
+		case outgoing:
+			dir = incoming
+		case incoming:
+			dir = outgoing
+		}
+	}
+
+	// У ненаправленного графа OutEdges уже содержит обе половины дуг
+	if !m.g.Traits().IsDirected {
+		dir = outgoing
+	}
+
+	res := make([]K, 0)
+
+	if dir == outgoing || dir == both {
+		edges, err := m.g.OutEdges(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, edge := range edges {
+			if accepts(rel, edge) {
+				res = append(res, edge.Target)
+			}
+		}
+	}
+
+	if dir == incoming || dir == both {
+		edges, err := m.g.InEdges(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, edge := range edges {
+			if accepts(rel, edge) {
+				res = append(res, edge.Source)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func accepts[K comparable](r relationship, edge graph.Edge[K]) bool {
+	return len(r.types) == 0 || slices.Contains(r.types, edge.Properties.Type)
+}
+
+// Записывает строку из текущих значений переменных, если такой ещё не было
+func (m *matcher[K, T]) emit() {
+	row := make([]K, len(m.result.Columns))
+
+	// Ключ строки собирается из вложенных пар: массивы из any сравнимы
+	var key any
+	for i, column := range m.result.Columns {
+		row[i] = m.bindings[column]
+		key = [2]any{key, row[i]}
+	}
+
+	if _, ok := m.seen[key]; ok {
+		return
+	}
+	m.seen[key] = struct{}{}
+
+	m.result.Rows = append(m.result.Rows, row)
+}
@@ -0,0 +1,125 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/IvanSaratov/graph_methods/graph"
+)
+
+func name(value string) func(*graph.VertexProperties) {
+	return func(p *graph.VertexProperties) {
+		p.Attributes["name"] = value
+	}
+}
+
+// Вершины 1..4 с атрибутом name и меткой Even у чётных.
+// Дуги NEXT идут цепочкой 1 -> 2 -> 3 -> 4, дуга SKIP ведёт из 1 в 3
+func testGraph(t *testing.T, options ...func(*graph.Traits)) graph.Graph[int, int] {
+	g := graph.New(graph.IntHash, options...)
+
+	for i := 1; i <= 4; i++ {
+		vertexOptions := []func(*graph.VertexProperties){name(fmt.Sprint(i))}
+		if i%2 == 0 {
+			vertexOptions = append(vertexOptions, graph.VertexLabels("Even"))
+		}
+
+		if err := g.AddVertex(i, vertexOptions...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 1; i < 4; i++ {
+		if err := g.AddEdge(i, i+1, graph.EdgeType("NEXT")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := g.AddEdge(1, 3, graph.EdgeType("SKIP")); err != nil {
+		t.Fatal(err)
+	}
+
+	return g
+}
+
+func TestMatch(t *testing.T) {
+	graphs := map[string]graph.Graph[int, int]{
+		"directed":   testGraph(t, graph.Directed()),
+		"undirected": testGraph(t),
+	}
+
+	tests := []struct {
+		graph string
+		text  string
+		want  [][]int
+	}{
+		// Длина пути
+		{"directed", `(a {name: "1"})-[:NEXT*0..]->(b) RETURN b`, [][]int{{1}, {2}, {3}, {4}}},
+		{"directed", `(a {name: "1"})-[:NEXT*1..2]->(b) RETURN b`, [][]int{{2}, {3}}},
+		{"directed", `(a {name: "1"})-[:NEXT*2]->(b) RETURN b`, [][]int{{3}}},
+		{"directed", `(a {name: "1"})-[*1..3]->(b) RETURN b`, [][]int{{2}, {3}, {4}}},
+		{"directed", `(a {name: "1"})-[*]->(b) RETURN b`, [][]int{{2}, {3}, {4}}},
+		{"directed", `(a {name: "2"})-[*2..]->(b) RETURN b`, [][]int{{4}}},
+		{"directed", `(a {name: "4"})<-[:NEXT*]-(b) RETURN b`, [][]int{{1}, {2}, {3}}},
+		{"directed", `(a {name: "4"})-[*]->(b) RETURN b`, [][]int{}},
+
+		// Направление и типы
+		{"directed", `(a)-[:SKIP]->(b)`, [][]int{{1, 3}}},
+		{"directed", `(a)<-[:SKIP]-(b)`, [][]int{{3, 1}}},
+		{"directed", `(a)-[:SKIP]-(b)`, [][]int{{1, 3}, {3, 1}}},
+		{"directed", `(a {name: "3"})<-[:NEXT|SKIP]-(b) RETURN b`, [][]int{{1}, {2}}},
+		// Начало шаблона у самой узкой вершины справа, шаг идёт против стрелки
+		{"directed", `(x)-[:NEXT]->(y {name: "3"}) RETURN x`, [][]int{{2}}},
+
+		// У ненаправленного графа стрелка не важна в обе стороны
+		{"undirected", `(a {name: "1"})<-[:NEXT]-(b) RETURN b`, [][]int{{2}}},
+		{"undirected", `(a {name: "1"})-[:NEXT]->(b) RETURN b`, [][]int{{2}}},
+		{"undirected", `(x)-[:NEXT]->(y {name: "3"}) RETURN x`, [][]int{{2}, {4}}},
+		{"undirected", `(a {name: "4"})<-[:NEXT*2]-(b) RETURN b`, [][]int{{2}, {4}}},
+		{"undirected", `(a {name: "4"})-[:NEXT*1..2]->(b) RETURN b`, [][]int{{2}, {3}, {4}}},
+		{"undirected", `(a {name: "1"})-[*0..]-(b) RETURN b`, [][]int{{1}, {2}, {3}, {4}}},
+
+		// Повторные переменные
+		{"directed", `(a)-[:NEXT]->(b), (b)-[:NEXT]->(c) RETURN a, c`, [][]int{{1, 3}, {2, 4}}},
+		{"directed", `(a)-[:SKIP]->(b), (a)-[:NEXT*2]->(b)`, [][]int{{1, 3}}},
+		{"directed", `(a)-[:NEXT]->(b)-[:NEXT]->(a)`, [][]int{}},
+		{"directed", `(a)-[:NEXT*0..]->(a) RETURN a`, [][]int{{1}, {2}, {3}, {4}}},
+		{"undirected", `(a)-[:NEXT]-(b)-[:NEXT]-(a)`, [][]int{{1, 2}, {2, 1}, {2, 3}, {3, 2}, {3, 4}, {4, 3}}},
+
+		// Метки, свойства и одинаковые строки
+		{"directed", `(a:Even)`, [][]int{{2}, {4}}},
+		{"directed", `(a:Even)-->(b:Even)`, [][]int{}},
+		{"directed", `(a)-->() RETURN a`, [][]int{{1}, {2}, {3}}},
+		{"directed", `(a {name: "9"})`, [][]int{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.graph+"/"+test.text, func(t *testing.T) {
+			res, err := Run(graphs[test.graph], test.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			slices.SortFunc(res.Rows, slices.Compare[[]int])
+			if !reflect.DeepEqual(res.Rows, test.want) {
+				t.Fatalf("строки %v, ожидалось %v", res.Rows, test.want)
+			}
+		})
+	}
+}
+
+func TestMatchColumns(t *testing.T) {
+	res, err := Run(testGraph(t, graph.Directed()), `(a)-[:SKIP]->(b) RETURN b, a`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(res.Columns, []string{"b", "a"}) {
+		t.Fatalf("столбцы %v", res.Columns)
+	}
+	if !reflect.DeepEqual(res.Rows, [][]int{{3, 1}}) {
+		t.Fatalf("строки %v", res.Rows)
+	}
+}
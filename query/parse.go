@@ -0,0 +1,540 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Разобранный запрос. Один запрос можно выполнять много раз через Match
+type Query struct {
+	patterns []pattern
+	// Переменные результата по порядку
+	columns []string
+}
+
+// Цепочка вершин и связей между ними: nodes[i] -[relationships[i]]- nodes[i+1]
+type pattern struct {
+	nodes         []node
+	relationships []relationship
+}
+
+type node struct {
+	// Пусто у безымянной вершины, она в результат не попадает
+	variable   string
+	labels     []string
+	attributes map[string]string
+}
+
+type direction int
+
+const (
+	// a-[]->b
+	outgoing direction = iota
+	// a<-[]-b
+	incoming
+	// a-[]-b
+	both
+)
+
+type relationship struct {
+	direction direction
+	// Подходящие типы дуг. Пусто значит любой
+	types []string
+	// Длина пути в дугах. max < 0 значит без ограничения
+	min, max int
+}
+
+// Разбирает запрос вида
+//
+//	MATCH (a:Person {team: "core"})-[:DEPENDS_ON*1..3]->(b), (b)<-[:OWNS]-(c) RETURN a, c
+//
+// MATCH и RETURN необязательны, без RETURN в результат идут все переменные.
+// Свойства в фигурных скобках сравниваются с Attributes вершины как строки,
+// метки после двоеточия проверяются по Labels.
+// У связи можно указать типы через | и длину: *, *2, *1..3, *..3, *2..
+func Parse(text string) (*Query, error) {
+	p := &parser{lexer: lexer{text: text}}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	return p.query()
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+type lexer struct {
+	text     string
+	position int
+}
+
+func (l *lexer) token() (token, error) {
+	for l.position < len(l.text) && unicode.IsSpace(rune(l.text[l.position])) {
+		l.position++
+	}
+
+	start := l.position
+	if start >= len(l.text) {
+		return token{kind: tokenEOF, position: start}, nil
+	}
+
+	c := l.text[start]
+
+	switch {
+	case isIdentStart(c):
+		for l.position < len(l.text) && isIdentPart(l.text[l.position]) {
+			l.position++
+		}
+		return token{kind: tokenIdent, text: l.text[start:l.position], position: start}, nil
+
+	case c >= '0' && c <= '9':
+		for l.position < len(l.text) && isDigit(l.text[l.position]) {
+			l.position++
+		}
+		// Дробная часть, но не ".." диапазона
+		if l.position+1 < len(l.text) && l.text[l.position] == '.' && isDigit(l.text[l.position+1]) {
+			l.position++
+			for l.position < len(l.text) && isDigit(l.text[l.position]) {
+				l.position++
+			}
+		}
+		return token{kind: tokenNumber, text: l.text[start:l.position], position: start}, nil
+
+	case c == '"' || c == '\'':
+		var value strings.Builder
+		for l.position++; l.position < len(l.text); l.position++ {
+			switch l.text[l.position] {
+			case c:
+				l.position++
+				return token{kind: tokenString, text: value.String(), position: start}, nil
+			case '\\':
+				l.position++
+				if l.position >= len(l.text) {
+					return token{}, syntaxError(start, "незакрытая строка")
+				}
+			}
+			value.WriteByte(l.text[l.position])
+		}
+		return token{}, syntaxError(start, "незакрытая строка")
+
+	case c == '.' && strings.HasPrefix(l.text[start:], ".."):
+		l.position += 2
+		return token{kind: tokenPunct, text: "..", position: start}, nil
+
+	case strings.IndexByte("()[]{}:,-<>*|", c) >= 0:
+		l.position++
+		return token{kind: tokenPunct, text: string(c), position: start}, nil
+	}
+
+	return token{}, syntaxError(start, fmt.Sprintf("неожиданный символ %q", c))
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func syntaxError(position int, message string) error {
+	return fmt.Errorf("%w: позиция %d: %s", ErrorSyntax, position, message)
+}
+
+// Разбор рекурсивным спуском, на один токен вперёд
+type parser struct {
+	lexer
+	current token
+}
+
+func (p *parser) next() error {
+	t, err := p.lexer.token()
+	if err != nil {
+		return err
+	}
+
+	p.current = t
+	return nil
+}
+
+func (p *parser) is(punct string) bool {
+	return p.current.kind == tokenPunct && p.current.text == punct
+}
+
+func (p *parser) keyword(word string) bool {
+	return p.current.kind == tokenIdent && strings.EqualFold(p.current.text, word)
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.is(punct) {
+		return p.unexpected("ожидалось " + punct)
+	}
+
+	return p.next()
+}
+
+func (p *parser) unexpected(message string) error {
+	if p.current.kind == tokenEOF {
+		return syntaxError(p.current.position, message+", а запрос закончился")
+	}
+
+	return syntaxError(p.current.position, fmt.Sprintf("%s, а встретилось %q", message, p.current.text))
+}
+
+func (p *parser) ident() (string, error) {
+	if p.current.kind != tokenIdent {
+		return "", p.unexpected("ожидалось имя")
+	}
+
+	name := p.current.text
+	return name, p.next()
+}
+
+func (p *parser) query() (*Query, error) {
+	q := &Query{}
+
+	if p.keyword("MATCH") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		pattern, err := p.pattern()
+		if err != nil {
+			return nil, err
+		}
+		q.patterns = append(q.patterns, pattern)
+
+		if !p.is(",") {
+			break
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	variables := q.variables()
+
+	if p.keyword("RETURN") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		for {
+			position := p.current.position
+			name, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+
+			if !slices.Contains(variables, name) {
+				return nil, fmt.Errorf("%w: позиция %d: %s", ErrorUnknownVariable, position, name)
+			}
+			q.columns = append(q.columns, name)
+
+			if !p.is(",") {
+				break
+			}
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		q.columns = variables
+	}
+
+	if p.current.kind != tokenEOF {
+		return nil, p.unexpected("ожидался конец запроса")
+	}
+
+	return q, nil
+}
+
+// Именованные переменные в порядке первого появления
+func (q *Query) variables() []string {
+	res := make([]string, 0)
+	for _, pattern := range q.patterns {
+		for _, node := range pattern.nodes {
+			if node.variable != "" && !slices.Contains(res, node.variable) {
+				res = append(res, node.variable)
+			}
+		}
+	}
+
+	return res
+}
+
+func (p *parser) pattern() (pattern, error) {
+	var res pattern
+
+	first, err := p.node()
+	if err != nil {
+		return res, err
+	}
+	res.nodes = append(res.nodes, first)
+
+	for p.is("-") || p.is("<") {
+		rel, err := p.relationship()
+		if err != nil {
+			return res, err
+		}
+
+		next, err := p.node()
+		if err != nil {
+			return res, err
+		}
+
+		res.relationships = append(res.relationships, rel)
+		res.nodes = append(res.nodes, next)
+	}
+
+	return res, nil
+}
+
+func (p *parser) node() (node, error) {
+	res := node{attributes: make(map[string]string)}
+
+	if err := p.expect("("); err != nil {
+		return res, err
+	}
+
+	if p.current.kind == tokenIdent {
+		res.variable = p.current.text
+		if err := p.next(); err != nil {
+			return res, err
+		}
+	}
+
+	for p.is(":") {
+		if err := p.next(); err != nil {
+			return res, err
+		}
+
+		label, err := p.ident()
+		if err != nil {
+			return res, err
+		}
+		res.labels = append(res.labels, label)
+	}
+
+	if p.is("{") {
+		if err := p.attributes(res.attributes); err != nil {
+			return res, err
+		}
+	}
+
+	return res, p.expect(")")
+}
+
+func (p *parser) attributes(attributes map[string]string) error {
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+
+	for !p.is("}") {
+		key, err := p.ident()
+		if err != nil {
+			return err
+		}
+
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+
+		value, err := p.value()
+		if err != nil {
+			return err
+		}
+		attributes[key] = value
+
+		if !p.is(",") {
+			break
+		}
+		if err := p.next(); err != nil {
+			return err
+		}
+	}
+
+	return p.expect("}")
+}
+
+// Строка или число. Атрибуты строковые, поэтому число сравнивается как записано
+func (p *parser) value() (string, error) {
+	sign := ""
+	if p.is("-") {
+		sign = "-"
+		if err := p.next(); err != nil {
+			return "", err
+		}
+	}
+
+	switch {
+	case p.current.kind == tokenNumber:
+		value := sign + p.current.text
+		return value, p.next()
+	case p.current.kind == tokenString && sign == "":
+		value := p.current.text
+		return value, p.next()
+	}
+
+	return "", p.unexpected("ожидалась строка или число")
+}
+
+// <-[...]-, -[...]->, -[...]- и те же стрелки без скобок: <--, -->, --
+func (p *parser) relationship() (relationship, error) {
+	res := relationship{direction: both, min: 1, max: 1}
+
+	left := p.is("<")
+	if left {
+		if err := p.next(); err != nil {
+			return res, err
+		}
+	}
+
+	if err := p.expect("-"); err != nil {
+		return res, err
+	}
+
+	if p.is("[") {
+		if err := p.relationshipBody(&res); err != nil {
+			return res, err
+		}
+	}
+
+	if err := p.expect("-"); err != nil {
+		return res, err
+	}
+
+	right := p.is(">")
+	if right {
+		if err := p.next(); err != nil {
+			return res, err
+		}
+	}
+
+	switch {
+	case left && right:
+		return res, syntaxError(p.current.position, "у связи два направления")
+	case left:
+		res.direction = incoming
+	case right:
+		res.direction = outgoing
+	}
+
+	return res, nil
+}
+
+func (p *parser) relationshipBody(res *relationship) error {
+	if err := p.expect("["); err != nil {
+		return err
+	}
+
+	if p.current.kind == tokenIdent {
+		return syntaxError(p.current.position, "переменные связей не поддерживаются")
+	}
+
+	if p.is(":") {
+		if err := p.next(); err != nil {
+			return err
+		}
+
+		for {
+			edgeType, err := p.ident()
+			if err != nil {
+				return err
+			}
+			res.types = append(res.types, edgeType)
+
+			if !p.is("|") {
+				break
+			}
+			if err := p.next(); err != nil {
+				return err
+			}
+			// Cypher разрешает и :A|:B
+			if p.is(":") {
+				if err := p.next(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if p.is("*") {
+		if err := p.length(res); err != nil {
+			return err
+		}
+	}
+
+	return p.expect("]")
+}
+
+// *, *n, *n..m, *..m, *n..
+func (p *parser) length(res *relationship) error {
+	position := p.current.position
+	if err := p.next(); err != nil {
+		return err
+	}
+
+	res.min, res.max = 1, -1
+
+	if p.current.kind == tokenNumber {
+		n, err := p.number()
+		if err != nil {
+			return err
+		}
+		res.min, res.max = n, n
+	}
+
+	if p.is("..") {
+		if err := p.next(); err != nil {
+			return err
+		}
+
+		res.max = -1
+		if p.current.kind == tokenNumber {
+			n, err := p.number()
+			if err != nil {
+				return err
+			}
+			res.max = n
+		}
+	}
+
+	if res.max >= 0 && res.min > res.max {
+		return syntaxError(position, "нижняя граница длины больше верхней")
+	}
+
+	return nil
+}
+
+func (p *parser) number() (int, error) {
+	n, err := strconv.Atoi(p.current.text)
+	if err != nil {
+		return 0, syntaxError(p.current.position, "длина должна быть целым числом")
+	}
+
+	return n, p.next()
+}
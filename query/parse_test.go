@@ -0,0 +1,174 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	a := node{variable: "a", attributes: map[string]string{}}
+	b := node{variable: "b", attributes: map[string]string{}}
+	c := node{variable: "c", attributes: map[string]string{}}
+	anonymous := node{attributes: map[string]string{}}
+
+	chain := func(nodes []node, relationships ...relationship) pattern {
+		return pattern{nodes: nodes, relationships: relationships}
+	}
+
+	tests := []struct {
+		text string
+		want *Query
+	}{
+		{
+			text: "MATCH (a)",
+			want: &Query{patterns: []pattern{chain([]node{a})}, columns: []string{"a"}},
+		},
+		{
+			text: "(a)-->(b)",
+			want: &Query{
+				patterns: []pattern{chain([]node{a, b}, relationship{direction: outgoing, min: 1, max: 1})},
+				columns:  []string{"a", "b"},
+			},
+		},
+		{
+			text: "(a)<--(b)",
+			want: &Query{
+				patterns: []pattern{chain([]node{a, b}, relationship{direction: incoming, min: 1, max: 1})},
+				columns:  []string{"a", "b"},
+			},
+		},
+		{
+			text: "(a)--(b)",
+			want: &Query{
+				patterns: []pattern{chain([]node{a, b}, relationship{direction: both, min: 1, max: 1})},
+				columns:  []string{"a", "b"},
+			},
+		},
+		{
+			text: "match (a:Person:Admin {team: \"core\", age: 30, delta: -1.5, quote: 'it\\'s'})-->() return a",
+			want: &Query{
+				patterns: []pattern{chain(
+					[]node{
+						{
+							variable:   "a",
+							labels:     []string{"Person", "Admin"},
+							attributes: map[string]string{"team": "core", "age": "30", "delta": "-1.5", "quote": "it's"},
+						},
+						anonymous,
+					},
+					relationship{direction: outgoing, min: 1, max: 1},
+				)},
+				columns: []string{"a"},
+			},
+		},
+		{
+			text: "(a)-[:OWNS|:USES|READS]-(b) RETURN b",
+			want: &Query{
+				patterns: []pattern{chain([]node{a, b}, relationship{direction: both, types: []string{"OWNS", "USES", "READS"}, min: 1, max: 1})},
+				columns:  []string{"b"},
+			},
+		},
+		{
+			text: "(a)-[*]->(b)",
+			want: &Query{
+				patterns: []pattern{chain([]node{a, b}, relationship{direction: outgoing, min: 1, max: -1})},
+				columns:  []string{"a", "b"},
+			},
+		},
+		{
+			text: "(a)-[*2]->(b)",
+			want: &Query{
+				patterns: []pattern{chain([]node{a, b}, relationship{direction: outgoing, min: 2, max: 2})},
+				columns:  []string{"a", "b"},
+			},
+		},
+		{
+			text: "(a)-[:NEXT*1..3]->(b)",
+			want: &Query{
+				patterns: []pattern{chain([]node{a, b}, relationship{direction: outgoing, types: []string{"NEXT"}, min: 1, max: 3})},
+				columns:  []string{"a", "b"},
+			},
+		},
+		{
+			text: "(a)-[*..3]->(b)",
+			want: &Query{
+				patterns: []pattern{chain([]node{a, b}, relationship{direction: outgoing, min: 1, max: 3})},
+				columns:  []string{"a", "b"},
+			},
+		},
+		{
+			text: "(a)<-[*0..]-(b)",
+			want: &Query{
+				patterns: []pattern{chain([]node{a, b}, relationship{direction: incoming, min: 0, max: -1})},
+				columns:  []string{"a", "b"},
+			},
+		},
+		{
+			text: "(a)-->(b)<--(c), (b)-->(a) RETURN c, a",
+			want: &Query{
+				patterns: []pattern{
+					chain([]node{a, b, c},
+						relationship{direction: outgoing, min: 1, max: 1},
+						relationship{direction: incoming, min: 1, max: 1},
+					),
+					chain([]node{b, a}, relationship{direction: outgoing, min: 1, max: 1}),
+				},
+				columns: []string{"c", "a"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			got, err := Parse(test.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("Parse(%q) =\n%+v\nожидалось\n%+v", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		text string
+		want error
+	}{
+		{"", ErrorSyntax},
+		{"MATCH", ErrorSyntax},
+		{"(a", ErrorSyntax},
+		{"(a))", ErrorSyntax},
+		{"(a) $", ErrorSyntax},
+		{"(a) (b)", ErrorSyntax},
+		{"(a)-(b)", ErrorSyntax},
+		{"(a)<-->(b)", ErrorSyntax},
+		{"(a)-[r]->(b)", ErrorSyntax},
+		{"(a)-[:]->(b)", ErrorSyntax},
+		{"(a)-[*3..1]->(b)", ErrorSyntax},
+		{"(a)-[*1.5]->(b)", ErrorSyntax},
+		{"(a)-[*x]->(b)", ErrorSyntax},
+		{"(a {x})", ErrorSyntax},
+		{"(a {x: })", ErrorSyntax},
+		{"(a {x: -\"s\"})", ErrorSyntax},
+		{"(a {x: \"open})", ErrorSyntax},
+		{"(a {x: \"open\\", ErrorSyntax},
+		{"(a:)", ErrorSyntax},
+		{"(a) RETURN", ErrorSyntax},
+		{"(a) RETURN a,", ErrorSyntax},
+		{"(a) RETURN b", ErrorUnknownVariable},
+		{"(a)-->() RETURN a, c", ErrorUnknownVariable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			_, err := Parse(test.text)
+			if !errors.Is(err, test.want) {
+				t.Fatalf("Parse(%q) вернул %v, ожидалось %v", test.text, err, test.want)
+			}
+		})
+	}
+}